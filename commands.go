@@ -0,0 +1,932 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli"
+
+	"github.com/orn688/advent-of-code-2018/internal/client"
+	"github.com/orn688/advent-of-code-2018/internal/day01"
+	"github.com/orn688/advent-of-code-2018/internal/day02"
+	"github.com/orn688/advent-of-code-2018/internal/day03"
+	"github.com/orn688/advent-of-code-2018/internal/day04"
+	"github.com/orn688/advent-of-code-2018/internal/day05"
+	"github.com/orn688/advent-of-code-2018/internal/day06"
+	"github.com/orn688/advent-of-code-2018/internal/day07"
+)
+
+var inputFlag = cli.StringFlag{
+	Name:  "input, i",
+	Usage: "read the puzzle input from `FILE` (- for stdin) instead of fetching it",
+}
+
+var metricFlag = cli.StringFlag{
+	Name:  "metric, m",
+	Value: "manhattan",
+	Usage: fmt.Sprintf("measure distances with `METRIC` (%s)", day06.MetricNames),
+}
+
+// partitionFlags returns the flags for drawing day 6's areas.
+func partitionFlags() []cli.Flag {
+	return []cli.Flag{
+		inputFlag,
+		metricFlag,
+		cli.IntFlag{
+			Name:  "margin",
+			Value: 1,
+			Usage: "draw `N` cells beyond the coordinates on every side",
+		},
+		cli.IntFlag{
+			Name:  "max-dist, d",
+			Value: day06.DefaultMaxDist,
+			Usage: "outline the cells with a total distance less than `N`",
+		},
+	}
+}
+
+var reverseFlag = cli.BoolFlag{
+	Name:  "reverse, r",
+	Usage: "build the available steps in reverse alphabetical order",
+}
+
+// durationFlags returns the flags for configuring how long day 7's steps
+// take.
+func durationFlags() []cli.Flag {
+	return []cli.Flag{
+		inputFlag,
+		cli.IntFlag{
+			Name:  "base, b",
+			Value: day07.DefaultSchedulerConfig.BaseDuration,
+			Usage: "add `SECONDS` to the duration of every step (step A takes this plus 1)",
+		},
+		cli.StringSliceFlag{
+			Name:  "duration, d",
+			Usage: "override a step's duration with `NAME=SECONDS`",
+		},
+	}
+}
+
+// scheduleFlags returns the flags for configuring day 7's workers.
+func scheduleFlags() []cli.Flag {
+	return append(durationFlags(), reverseFlag, cli.IntFlag{
+		Name:  "workers, w",
+		Value: day07.DefaultSchedulerConfig.Workers,
+		Usage: "build the steps with `N` workers",
+	})
+}
+
+// dayCommands returns the subcommands that expose more of a day's solution
+// than the answers printed by the default action.
+func dayCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:  "day01",
+			Usage: "explore the frequency changes from day 1",
+			Subcommands: []cli.Command{
+				{
+					Name:   "repeat",
+					Usage:  "show when the first repeated frequency is reached",
+					Flags:  []cli.Flag{inputFlag},
+					Action: day01Repeat,
+				},
+				{
+					Name:  "trace",
+					Usage: "print the frequency after each change, up to the first repeat",
+					Flags: []cli.Flag{
+						inputFlag,
+						formatFlag("csv", "json"),
+						cli.IntFlag{
+							Name:  "max-steps",
+							Usage: "stop the trace after `N` steps",
+							Value: day01.DefaultMaxTraceSteps,
+						},
+					},
+					Action: day01Trace,
+				},
+			},
+		},
+		{
+			Name:  "day02",
+			Usage: "explore the box IDs from day 2",
+			Subcommands: []cli.Command{
+				{
+					Name:  "near",
+					Usage: "list every pair of box IDs within some edit distance",
+					Flags: []cli.Flag{
+						inputFlag,
+						formatFlag("text", "json"),
+						cli.IntFlag{
+							Name:  "k",
+							Value: 1,
+							Usage: "maximum edit distance `K` between matching IDs",
+						},
+					},
+					Action: day02Near,
+				},
+				{
+					Name:  "checksum",
+					Usage: "compute the checksum for any set of letter multiplicities",
+					Flags: []cli.Flag{
+						inputFlag,
+						cli.IntSliceFlag{
+							Name:  "multiplicity, m",
+							Usage: "count IDs with a letter repeated exactly `N` times (default 2 and 3)",
+						},
+						cli.BoolFlag{
+							Name:  "verbose, v",
+							Usage: "also list the repeated letters in every ID",
+						},
+					},
+					Action: day02Checksum,
+				},
+			},
+		},
+		{
+			Name:  "day03",
+			Usage: "explore the fabric claims from day 3",
+			Subcommands: []cli.Command{
+				{
+					Name:      "covers",
+					Usage:     "list the IDs of the claims covering a square, as JSON",
+					ArgsUsage: "<x> <y>",
+					Flags:     []cli.Flag{inputFlag},
+					Action:    day03Covers,
+				},
+				{
+					Name:      "conflicts",
+					Usage:     "list the IDs of the claims overlapping a claim, as JSON",
+					ArgsUsage: "<claim-id>",
+					Flags:     []cli.Flag{inputFlag},
+					Action:    day03Conflicts,
+				},
+				{
+					Name:   "graph",
+					Usage:  "print the conflict graph as a JSON adjacency list",
+					Flags:  []cli.Flag{inputFlag},
+					Action: day03Graph,
+				},
+				{
+					Name:  "render",
+					Usage: "draw the claims as a PNG or SVG, depending on the output file's extension",
+					Flags: []cli.Flag{
+						inputFlag,
+						cli.StringFlag{
+							Name:  "output, o",
+							Value: "fabric.png",
+							Usage: "write the image to `FILE` (.png or .svg)",
+						},
+					},
+					Action: day03Render,
+				},
+			},
+		},
+		{
+			Name:  "day04",
+			Usage: "explore the guard sleep log from day 4",
+			Subcommands: []cli.Command{
+				{
+					Name:   "table",
+					Usage:  "print which minutes the guard on duty was asleep each night",
+					Flags:  []cli.Flag{inputFlag, formatFlag("text", "csv")},
+					Action: day04Table,
+				},
+				{
+					Name:   "summary",
+					Usage:  "print each guard's total sleep, nap count and sleepiest minute",
+					Flags:  []cli.Flag{inputFlag, formatFlag("text", "csv")},
+					Action: day04Summary,
+				},
+				{
+					Name:  "rank",
+					Usage: "rank every guard under one of the strategies, warning about ties",
+					Flags: []cli.Flag{
+						inputFlag,
+						cli.IntFlag{
+							Name:  "strategy, s",
+							Value: 1,
+							Usage: "rank by strategy `N` (1 or 2)",
+						},
+					},
+					Action: day04Rank,
+				},
+			},
+		},
+		{
+			Name:  "day05",
+			Usage: "explore the polymer from day 5",
+			Subcommands: []cli.Command{
+				{
+					Name:  "reduce",
+					Usage: "print the units that survive reduction (the JSON format includes their original positions)",
+					Flags: []cli.Flag{
+						inputFlag,
+						formatFlag("text", "json"),
+						cli.StringFlag{
+							Name:  "rules, r",
+							Usage: "react the pairs of units listed in `FILE`, one pair per line, instead of opposite cases",
+						},
+					},
+					Action: day05Reduce,
+				},
+				{
+					Name:  "stream",
+					Usage: "print the length of the reduced polymer without reading it all into memory",
+					Flags: []cli.Flag{
+						inputFlag,
+						cli.BoolFlag{
+							Name:  "progress, p",
+							Usage: "report progress on stderr",
+						},
+					},
+					Action: day05Stream,
+				},
+				{
+					Name:  "generate",
+					Usage: "write a random polymer, e.g. to pipe into stream",
+					Flags: []cli.Flag{
+						cli.Int64Flag{
+							Name:  "length, n",
+							Value: 50000,
+							Usage: "generate `N` units",
+						},
+						cli.IntFlag{
+							Name:  "types, t",
+							Value: 26,
+							Usage: "use the first `N` letters of the alphabet",
+						},
+						cli.Int64Flag{
+							Name:  "seed, s",
+							Value: 1,
+							Usage: "seed the generator with `SEED`",
+						},
+					},
+					Action: day05Generate,
+				},
+			},
+		},
+		{
+			Name:  "day06",
+			Usage: "explore the coordinates from day 6",
+			Subcommands: []cli.Command{
+				{
+					Name:  "safe",
+					Usage: "print the size of the region within some total distance of every coordinate",
+					Flags: []cli.Flag{
+						inputFlag,
+						cli.IntFlag{
+							Name:  "max-dist, d",
+							Value: day06.DefaultMaxDist,
+							Usage: "count cells with a total distance less than `N`",
+						},
+						metricFlag,
+					},
+					Action: day06Safe,
+				},
+				{
+					Name:   "largest",
+					Usage:  "print the size of the largest finite area",
+					Flags:  []cli.Flag{inputFlag, metricFlag},
+					Action: day06Largest,
+				},
+				{
+					Name:  "partitions",
+					Usage: "draw the areas under several metrics side by side",
+					Flags: []cli.Flag{
+						inputFlag,
+						cli.StringSliceFlag{
+							Name:  "metric, m",
+							Usage: fmt.Sprintf("draw the areas under `METRIC` (%s; default manhattan, chebyshev and squared-euclidean)", day06.MetricNames),
+						},
+						cli.IntFlag{
+							Name:  "margin",
+							Value: 1,
+							Usage: "draw `N` cells beyond the coordinates on every side",
+						},
+					},
+					Action: day06Partitions,
+				},
+				{
+					Name:  "render",
+					Usage: "draw the areas and the safe region as a PNG",
+					Flags: append(partitionFlags(), cli.StringFlag{
+						Name:  "output, o",
+						Value: "areas.png",
+						Usage: "write the image to `FILE`",
+					}),
+					Action: day06Render,
+				},
+				{
+					Name:   "show",
+					Usage:  "draw the areas and the safe region in color in the terminal",
+					Flags:  partitionFlags(),
+					Action: day06Show,
+				},
+			},
+		},
+		{
+			Name:  "day07",
+			Usage: "explore the assembly steps from day 7",
+			Subcommands: []cli.Command{
+				{
+					Name:   "order",
+					Usage:  "print the order in which a single worker builds the steps",
+					Flags:  []cli.Flag{inputFlag, reverseFlag},
+					Action: day07Order,
+				},
+				{
+					Name:   "schedule",
+					Usage:  "print the time it takes the workers to build every step",
+					Flags:  scheduleFlags(),
+					Action: day07Schedule,
+				},
+				{
+					Name:   "timeline",
+					Usage:  "show what each worker does and when, as a table, a Gantt chart or JSON",
+					Flags:  append(scheduleFlags(), formatFlag("table", "svg", "json")),
+					Action: day07Timeline,
+				},
+				{
+					Name:   "critical",
+					Usage:  "print the critical path and the fewest workers that finish in its time",
+					Flags:  append(durationFlags(), reverseFlag, formatFlag("text", "json")),
+					Action: day07Critical,
+				},
+				{
+					Name:   "dot",
+					Usage:  "print the dependency graph in Graphviz's DOT language, highlighting the critical path",
+					Flags:  durationFlags(),
+					Action: day07DOT,
+				},
+			},
+		},
+	}
+}
+
+// formatFlag returns a --format flag accepting the given output formats, the
+// first of which is the default.
+func formatFlag(formats ...string) cli.StringFlag {
+	return cli.StringFlag{
+		Name:  "format, f",
+		Value: formats[0],
+		Usage: fmt.Sprintf("output format (one of %s)", strings.Join(formats, ", ")),
+	}
+}
+
+func unknownFormatError(context *cli.Context) error {
+	return fmt.Errorf("unknown format %q", context.String("format"))
+}
+
+func writeJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// intArgs parses the command's positional arguments, which must be exactly
+// count integers.
+func intArgs(context *cli.Context, count int) ([]int, error) {
+	if context.NArg() != count {
+		return nil, fmt.Errorf("expected %d arguments, got %d", count, context.NArg())
+	}
+	nums := make([]int, count)
+	for i, arg := range context.Args() {
+		num, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, err
+		}
+		nums[i] = num
+	}
+	return nums, nil
+}
+
+// writeFile creates the named file and fills it using write.
+func writeFile(fileName string, write func(io.Writer) error) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// openInput opens the file given by --input, stdin if it's "-", or the AoC
+// input for the given day if there isn't one.
+func openInput(context *cli.Context, day int) (io.ReadCloser, error) {
+	switch fileName := context.String("input"); fileName {
+	case "":
+		input, err := client.GetInput(day)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(strings.NewReader(input)), nil
+	case "-":
+		return ioutil.NopCloser(os.Stdin), nil
+	default:
+		return os.Open(fileName)
+	}
+}
+
+// readInput returns the whole of the input opened by openInput.
+func readInput(context *cli.Context, day int) (string, error) {
+	reader, err := openInput(context, day)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	rawInput, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(rawInput), nil
+}
+
+func day01Repeat(context *cli.Context) error {
+	input, err := readInput(context, 1)
+	if err != nil {
+		return err
+	}
+	repeat, err := day01.FirstRepeat(input)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("frequency %d is repeated by change #%d during pass #%d\n",
+		repeat.Frequency, repeat.Index+1, repeat.Iteration+1)
+	return nil
+}
+
+func day01Trace(context *cli.Context) error {
+	input, err := readInput(context, 1)
+	if err != nil {
+		return err
+	}
+	trace, err := day01.TraceFrequencies(input, context.Int("max-steps"))
+	if err != nil {
+		return err
+	}
+	switch context.String("format") {
+	case "csv":
+		return trace.WriteCSV(os.Stdout)
+	case "json":
+		return trace.WriteJSON(os.Stdout)
+	default:
+		return unknownFormatError(context)
+	}
+}
+
+func day02Near(context *cli.Context) error {
+	input, err := readInput(context, 2)
+	if err != nil {
+		return err
+	}
+	matches := day02.FindNearMatches(input, context.Int("k"))
+	switch context.String("format") {
+	case "text":
+		for _, match := range matches {
+			positions := make([]string, len(match.Edits))
+			for i, edit := range match.Edits {
+				positions[i] = strconv.Itoa(edit.Pos)
+			}
+			fmt.Printf("%s %s distance %d, differing at %s\n", match.A, match.B,
+				match.Distance, strings.Join(positions, ","))
+		}
+		return nil
+	case "json":
+		return writeJSON(matches)
+	default:
+		return unknownFormatError(context)
+	}
+}
+
+func day02Checksum(context *cli.Context) error {
+	input, err := readInput(context, 2)
+	if err != nil {
+		return err
+	}
+	multiplicities := context.IntSlice("multiplicity")
+	if len(multiplicities) == 0 {
+		multiplicities = []int{2, 3}
+	}
+	if context.Bool("verbose") {
+		for _, report := range day02.FrequencyReports(input) {
+			fmt.Println(report)
+		}
+	}
+	fmt.Println(day02.Checksum(input, multiplicities))
+	return nil
+}
+
+func readFabric(context *cli.Context) (*day03.Fabric, error) {
+	input, err := readInput(context, 3)
+	if err != nil {
+		return nil, err
+	}
+	return day03.NewFabric(input)
+}
+
+func day03Covers(context *cli.Context) error {
+	args, err := intArgs(context, 2)
+	if err != nil {
+		return err
+	}
+	fabric, err := readFabric(context)
+	if err != nil {
+		return err
+	}
+	return writeJSON(fabric.ClaimsCovering(args[0], args[1]))
+}
+
+func day03Conflicts(context *cli.Context) error {
+	args, err := intArgs(context, 1)
+	if err != nil {
+		return err
+	}
+	fabric, err := readFabric(context)
+	if err != nil {
+		return err
+	}
+	conflicts, err := fabric.Conflicts(args[0])
+	if err != nil {
+		return err
+	}
+	return writeJSON(conflicts)
+}
+
+func day03Graph(context *cli.Context) error {
+	fabric, err := readFabric(context)
+	if err != nil {
+		return err
+	}
+	return writeJSON(fabric.ConflictGraph())
+}
+
+func day03Render(context *cli.Context) error {
+	fabric, err := readFabric(context)
+	if err != nil {
+		return err
+	}
+	fileName := context.String("output")
+	switch filepath.Ext(fileName) {
+	case ".png":
+		return writeFile(fileName, fabric.WritePNG)
+	case ".svg":
+		return writeFile(fileName, fabric.WriteSVG)
+	default:
+		return fmt.Errorf("can't render to %s, expected a .png or .svg file", fileName)
+	}
+}
+
+func readSleepReport(context *cli.Context) (day04.SleepReport, error) {
+	input, err := readInput(context, 4)
+	if err != nil {
+		return day04.SleepReport{}, err
+	}
+	return day04.NewSleepReport(input)
+}
+
+func day04Table(context *cli.Context) error {
+	report, err := readSleepReport(context)
+	if err != nil {
+		return err
+	}
+	switch context.String("format") {
+	case "text":
+		return report.WriteTable(os.Stdout)
+	case "csv":
+		return report.WriteTableCSV(os.Stdout)
+	default:
+		return unknownFormatError(context)
+	}
+}
+
+func day04Summary(context *cli.Context) error {
+	report, err := readSleepReport(context)
+	if err != nil {
+		return err
+	}
+	switch context.String("format") {
+	case "text":
+		return report.WriteSummary(os.Stdout)
+	case "csv":
+		return report.WriteSummaryCSV(os.Stdout)
+	default:
+		return unknownFormatError(context)
+	}
+}
+
+func rankDay04(input string, strategy int) (day04.Ranking, error) {
+	switch strategy {
+	case 1:
+		return day04.RankStrategy1(input)
+	case 2:
+		return day04.RankStrategy2(input)
+	default:
+		return nil, fmt.Errorf("unknown strategy %d", strategy)
+	}
+}
+
+func day04Rank(context *cli.Context) error {
+	input, err := readInput(context, 4)
+	if err != nil {
+		return err
+	}
+	ranking, err := rankDay04(input, context.Int("strategy"))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-8s %6s %7s  %s\n", "Guard", "Score", "Minute", "Tied minutes")
+	for _, guard := range ranking {
+		tied := make([]string, len(guard.TiedMinutes))
+		for i, minute := range guard.TiedMinutes {
+			tied[i] = strconv.Itoa(minute)
+		}
+		row := fmt.Sprintf("%-8s %6d %7d  %s", "#"+strconv.Itoa(guard.GuardID),
+			guard.Score, guard.Minute, strings.Join(tied, ","))
+		fmt.Println(strings.TrimRight(row, " "))
+	}
+	fmt.Printf("answer: %d\n", ranking.Answer())
+	for _, caveat := range ranking.Caveats() {
+		warn(caveat)
+	}
+	return nil
+}
+
+func day05Reduce(context *cli.Context) error {
+	input, err := readInput(context, 5)
+	if err != nil {
+		return err
+	}
+	var rules day05.Rules = day05.CaseRules{}
+	if fileName := context.String("rules"); fileName != "" {
+		rawRules, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		if rules, err = day05.ParsePairRules(string(rawRules)); err != nil {
+			return err
+		}
+	}
+	units := day05.Reduce(strings.TrimSpace(input), rules)
+	switch context.String("format") {
+	case "text":
+		fmt.Println(day05.String(units))
+		return nil
+	case "json":
+		return writeJSON(units)
+	default:
+		return unknownFormatError(context)
+	}
+}
+
+func day05Stream(context *cli.Context) error {
+	reader, err := openInput(context, 5)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	var progress func(day05.Progress)
+	if context.Bool("progress") {
+		progress = func(progress day05.Progress) {
+			fmt.Fprintf(os.Stderr, "read %d bytes, %d units, %d surviving\n",
+				progress.Bytes, progress.Units, progress.Surviving)
+		}
+	}
+	reducer, err := day05.ReduceReader(reader, day05.CaseRules{}, false, progress)
+	if err != nil {
+		return err
+	}
+	fmt.Println(reducer.Len())
+	return nil
+}
+
+func day05Generate(context *cli.Context) error {
+	return day05.GeneratePolymer(os.Stdout, context.Int64("length"), context.Int("types"), context.Int64("seed"))
+}
+
+func day06Safe(context *cli.Context) error {
+	input, err := readInput(context, 6)
+	if err != nil {
+		return err
+	}
+	metric, err := day06.ParseMetric(context.String("metric"))
+	if err != nil {
+		return err
+	}
+	size, err := day06.SafeRegionSize(input, context.Int("max-dist"), metric)
+	if err != nil {
+		return err
+	}
+	fmt.Println(size)
+	return nil
+}
+
+func day06Largest(context *cli.Context) error {
+	input, err := readInput(context, 6)
+	if err != nil {
+		return err
+	}
+	metric, err := day06.ParseMetric(context.String("metric"))
+	if err != nil {
+		return err
+	}
+	size, err := day06.LargestFiniteArea(input, metric)
+	if err != nil {
+		return err
+	}
+	fmt.Println(size)
+	return nil
+}
+
+func day06Partitions(context *cli.Context) error {
+	input, err := readInput(context, 6)
+	if err != nil {
+		return err
+	}
+	names := context.StringSlice("metric")
+	if len(names) == 0 {
+		names = []string{"manhattan", "chebyshev", "squared-euclidean"}
+	}
+	columns := make([][]string, len(names))
+	for i, name := range names {
+		metric, err := day06.ParseMetric(name)
+		if err != nil {
+			return err
+		}
+		partition, err := day06.NewPartition(input, metric, context.Int("margin"), 0)
+		if err != nil {
+			return err
+		}
+		columns[i] = append([]string{name, ""}, partition.Rows()...)
+	}
+	width := 0
+	for _, name := range names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	if len(columns[0][2]) > width {
+		// Every partition covers the same window, so they're all as wide.
+		width = len(columns[0][2])
+	}
+	for row := range columns[0] {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = fmt.Sprintf("%-*s", width, column[row])
+		}
+		fmt.Println(strings.TrimRight(strings.Join(cells, "   "), " "))
+	}
+	return nil
+}
+
+func readPartition(context *cli.Context) (*day06.Partition, error) {
+	input, err := readInput(context, 6)
+	if err != nil {
+		return nil, err
+	}
+	metric, err := day06.ParseMetric(context.String("metric"))
+	if err != nil {
+		return nil, err
+	}
+	return day06.NewPartition(input, metric, context.Int("margin"), context.Int("max-dist"))
+}
+
+func day06Render(context *cli.Context) error {
+	partition, err := readPartition(context)
+	if err != nil {
+		return err
+	}
+	return writeFile(context.String("output"), partition.WritePNG)
+}
+
+func day06Show(context *cli.Context) error {
+	partition, err := readPartition(context)
+	if err != nil {
+		return err
+	}
+	return partition.WriteANSI(os.Stdout)
+}
+
+func day07Order(context *cli.Context) error {
+	input, err := readInput(context, 7)
+	if err != nil {
+		return err
+	}
+	order, err := day07.BuildOrder(input, context.Bool("reverse"))
+	if err != nil {
+		return err
+	}
+	fmt.Println(order)
+	return nil
+}
+
+func schedulerConfig(context *cli.Context) (day07.SchedulerConfig, error) {
+	durations, err := day07.ParseDurations(context.StringSlice("duration"))
+	if err != nil {
+		return day07.SchedulerConfig{}, err
+	}
+	return day07.SchedulerConfig{
+		Workers:      context.Int("workers"),
+		BaseDuration: context.Int("base"),
+		Durations:    durations,
+		Reverse:      context.Bool("reverse"),
+	}, nil
+}
+
+func day07Schedule(context *cli.Context) error {
+	input, err := readInput(context, 7)
+	if err != nil {
+		return err
+	}
+	config, err := schedulerConfig(context)
+	if err != nil {
+		return err
+	}
+	time, err := day07.TimeToComplete(input, config)
+	if err != nil {
+		return err
+	}
+	fmt.Println(time)
+	return nil
+}
+
+func day07Timeline(context *cli.Context) error {
+	input, err := readInput(context, 7)
+	if err != nil {
+		return err
+	}
+	config, err := schedulerConfig(context)
+	if err != nil {
+		return err
+	}
+	timeline, err := day07.Schedule(input, config)
+	if err != nil {
+		return err
+	}
+	switch context.String("format") {
+	case "table":
+		return timeline.WriteTable(os.Stdout)
+	case "svg":
+		return timeline.WriteSVG(os.Stdout)
+	case "json":
+		return writeJSON(timeline)
+	default:
+		return unknownFormatError(context)
+	}
+}
+
+func day07Critical(context *cli.Context) error {
+	input, err := readInput(context, 7)
+	if err != nil {
+		return err
+	}
+	config, err := schedulerConfig(context)
+	if err != nil {
+		return err
+	}
+	path, err := day07.FindCriticalPath(input, config)
+	if err != nil {
+		return err
+	}
+	workers, err := day07.MinWorkers(input, config)
+	if err != nil {
+		return err
+	}
+	switch context.String("format") {
+	case "text":
+		fmt.Printf("critical path %s takes %d seconds, which %d workers can match\n",
+			strings.Join(path.Steps, " -> "), path.Time, workers)
+		return nil
+	case "json":
+		return writeJSON(struct {
+			day07.CriticalPath
+			MinWorkers int `json:"minWorkers"`
+		}{path, workers})
+	default:
+		return unknownFormatError(context)
+	}
+}
+
+func day07DOT(context *cli.Context) error {
+	input, err := readInput(context, 7)
+	if err != nil {
+		return err
+	}
+	config, err := schedulerConfig(context)
+	if err != nil {
+		return err
+	}
+	return day07.WriteDOT(os.Stdout, input, config)
+}
+
+func warn(warning string) {
+	fmt.Fprintln(os.Stderr, "warning:", warning)
+}
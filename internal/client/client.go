@@ -9,7 +9,9 @@ import (
 	"strconv"
 )
 
-const cacheDirName = ".aoc_cache"
+// CacheDirName is the directory, relative to the working directory, in which
+// downloaded inputs are cached.
+const CacheDirName = ".aoc_cache"
 
 // GetInput fetches and returns the AoC input for the given day. It maintains a
 // local cache of the input for each day (in .aoc_cache/<day>) to avoid making
@@ -97,5 +99,5 @@ func getCacheDir() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return path.Join(currentDir, cacheDirName), nil
+	return path.Join(currentDir, CacheDirName), nil
 }
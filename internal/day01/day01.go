@@ -1,11 +1,34 @@
 package day01
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 )
 
+// A Repeat describes the first frequency that is reached twice.
+type Repeat struct {
+	Frequency int `json:"frequency"`
+	// Iteration is the number of complete passes through the list of changes
+	// made before the repeat (so 0 means it happened during the first pass).
+	Iteration int `json:"iteration"`
+	// Index is the position in the list of the change that led to the repeat.
+	Index int `json:"index"`
+}
+
+// A NeverRepeatsError is returned when the frequency drifts away forever
+// without reaching any value twice.
+type NeverRepeatsError struct {
+	// Drift is the total change in frequency over one pass through the list.
+	Drift int
+}
+
+func (e *NeverRepeatsError) Error() string {
+	return fmt.Sprintf("no frequency is ever repeated (drift per pass is %+d)", e.Drift)
+}
+
 // Part1 returns the frequency at the end.
 func Part1(input string) (string, error) {
 	diffs, err := parseInput(input)
@@ -21,22 +44,112 @@ func Part1(input string) (string, error) {
 
 // Part2 returns the first frequency to be hit twice.
 func Part2(input string) (string, error) {
-	diffs, err := parseInput(input)
+	repeat, err := FirstRepeat(input)
 	if err != nil {
 		return "", err
 	}
-	seen := make(map[int]bool)
+	return strconv.Itoa(repeat.Frequency), nil
+}
+
+// FirstRepeat finds the first frequency to be hit twice without simulating
+// pass after pass through the changes, so it also detects inputs that never
+// repeat.
+func FirstRepeat(input string) (Repeat, error) {
+	diffs, err := parseInput(input)
+	if err != nil {
+		return Repeat{}, err
+	}
+	return firstRepeat(diffs)
+}
+
+// firstRepeat takes O(n log n) time for n changes.
+//
+// The frequency after the change at index i during pass k is p[i] + k*drift,
+// where p holds the prefix sums of the first pass and drift is the final
+// prefix sum. Any repeat that doesn't happen during the first pass must be of
+// a value from the first pass (or the starting 0), and p[i] can only ever
+// reach a value v if the two are congruent modulo the drift and v lies in the
+// direction of the drift. So grouping the values by residue and sorting them
+// gives each p[i] its nearest reachable value, and the earliest of those is
+// the answer.
+func firstRepeat(diffs []int) (Repeat, error) {
+	if len(diffs) == 0 {
+		return Repeat{}, errors.New("no frequency changes in input")
+	}
+
+	// The first pass is simulated directly, which also gives the prefix sums.
+	prefixSums := make([]int, len(diffs))
+	seen := map[int]bool{0: true}
 	frequency := 0
-	i := 0
-	for {
-		frequency += diffs[i]
+	for i, diff := range diffs {
+		frequency += diff
 		if seen[frequency] {
-			break
+			return Repeat{Frequency: frequency, Iteration: 0, Index: i}, nil
 		}
 		seen[frequency] = true
-		i = (i + 1) % len(diffs)
+		prefixSums[i] = frequency
 	}
-	return strconv.Itoa(frequency), nil
+	// The drift can't be 0 here, since the pass would have ended by repeating
+	// the starting frequency.
+	drift := frequency
+
+	type value struct {
+		frequency int
+		residue   int
+		index     int // -1 for the starting frequency
+	}
+	values := make([]value, 0, len(diffs)+1)
+	values = append(values, value{0, 0, -1})
+	for i, sum := range prefixSums {
+		values = append(values, value{sum, modulo(sum, drift), i})
+	}
+	// Sort so that each value's nearest reachable value comes right after it.
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].residue != values[j].residue {
+			return values[i].residue < values[j].residue
+		}
+		if drift > 0 {
+			return values[i].frequency < values[j].frequency
+		}
+		return values[i].frequency > values[j].frequency
+	})
+
+	found := false
+	var best Repeat
+	for i := 0; i+1 < len(values); i++ {
+		source, target := values[i], values[i+1]
+		if source.index < 0 || source.residue != target.residue {
+			continue
+		}
+		passes := (target.frequency - source.frequency) / drift
+		repeat := Repeat{Frequency: target.frequency, Iteration: passes, Index: source.index}
+		if !found || repeat.steps(len(diffs)) < best.steps(len(diffs)) {
+			best = repeat
+			found = true
+		}
+	}
+	if !found {
+		return Repeat{}, &NeverRepeatsError{Drift: drift}
+	}
+	return best, nil
+}
+
+// steps returns the number of changes applied up to and including the one
+// that produced the repeat.
+func (r Repeat) steps(changeCount int) int {
+	return r.Iteration*changeCount + r.Index + 1
+}
+
+// modulo returns x mod m in the range [0, |m|).
+func modulo(x, m int) int {
+	if m < 0 {
+		m = -m
+	}
+	r := x % m
+	if r < 0 {
+		r += m
+	}
+	return r
 }
 
 func parseInput(input string) ([]int, error) {
@@ -0,0 +1,146 @@
+package day01
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/orn688/advent-of-code-2018/internal/testutil"
+)
+
+func changes(diffs ...string) string {
+	return strings.Join(diffs, "\n")
+}
+
+func TestPart1(t *testing.T) {
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Input: changes("+1", "+1", "+1"), Expected: "3"},
+		{Input: changes("+1", "+1", "-2"), Expected: "0"},
+		{Input: changes("-1", "-2", "-3"), Expected: "-6"},
+	})
+}
+
+func TestPart2(t *testing.T) {
+	testutil.RunExamples(t, Part2, []testutil.Example{
+		{Input: changes("+1", "-1"), Expected: "0"},
+		{Input: changes("+3", "+3", "+4", "-2", "-4"), Expected: "10"},
+		{Input: changes("-6", "+3", "+8", "+5", "-6"), Expected: "5"},
+		{Input: changes("+7", "+7", "-2", "-7", "-4"), Expected: "14"},
+	})
+}
+
+func TestFirstRepeatNeverRepeats(t *testing.T) {
+	for _, input := range []string{changes("+1", "+1"), changes("-3", "+1")} {
+		_, err := FirstRepeat(input)
+		if _, ok := err.(*NeverRepeatsError); !ok {
+			t.Errorf("%q: expected a NeverRepeatsError, actual %v", input, err)
+		}
+	}
+}
+
+func TestFirstRepeatMatchesSimulation(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 2000; trial++ {
+		diffs := make([]int, 1+random.Intn(8))
+		for i := range diffs {
+			diffs[i] = random.Intn(21) - 10
+		}
+		expected, simulated := simulateFirstRepeat(diffs, 100)
+		actual, err := firstRepeat(diffs)
+		if !simulated {
+			// The simulation gave up, so there should either be no repeat at
+			// all or one after more passes than were simulated.
+			if err == nil && actual.Iteration < 100 {
+				t.Errorf("%v: expected no early repeat, actual %+v", diffs, actual)
+			}
+			continue
+		}
+		if err != nil || actual != expected {
+			t.Errorf("%v: expected %+v, actual %+v (%v)", diffs, expected, actual, err)
+		}
+	}
+}
+
+// simulateFirstRepeat applies the changes pass after pass, giving up after
+// maxPasses.
+func simulateFirstRepeat(diffs []int, maxPasses int) (Repeat, bool) {
+	seen := map[int]bool{0: true}
+	frequency := 0
+	for pass := 0; pass < maxPasses; pass++ {
+		for i, diff := range diffs {
+			frequency += diff
+			if seen[frequency] {
+				return Repeat{Frequency: frequency, Iteration: pass, Index: i}, true
+			}
+			seen[frequency] = true
+		}
+	}
+	return Repeat{}, false
+}
+
+func TestTraceFrequencies(t *testing.T) {
+	trace, err := TraceFrequencies(changes("+3", "+3", "+4", "-2", "-4"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trace.Steps) != 7 || trace.StepsBeforeRepeat != 6 {
+		t.Errorf("expected 7 steps with 6 before the repeat, actual %d and %d",
+			len(trace.Steps), trace.StepsBeforeRepeat)
+	}
+	last := trace.Steps[len(trace.Steps)-1]
+	if last.Frequency != 10 || last.Pass != 1 || last.Index != 1 {
+		t.Errorf("expected the last step to reach 10 at pass 1, index 1, actual %+v", last)
+	}
+	if trace.Min != 0 || trace.Max != 10 || trace.Drift != 4 {
+		t.Errorf("expected min 0, max 10 and drift 4, actual %d, %d and %d",
+			trace.Min, trace.Max, trace.Drift)
+	}
+
+	var sb strings.Builder
+	if err := trace.WriteCSV(&sb); err != nil {
+		t.Fatal(err)
+	}
+	expected := `
+step,pass,index,change,frequency
+1,0,0,3,3
+2,0,1,3,6
+3,0,2,4,10
+4,0,3,-2,8
+5,0,4,-4,4
+6,1,0,3,7
+7,1,1,3,10
+`
+	testutil.AssertGridEqual(t, expected, sb.String())
+}
+
+func TestTraceFrequenciesNeverRepeats(t *testing.T) {
+	trace, err := TraceFrequencies(changes("-2", "-2", "+1"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trace.Repeat != nil || trace.StepsBeforeRepeat != -1 {
+		t.Errorf("expected no repeat, actual %+v", trace.Repeat)
+	}
+	if len(trace.Steps) != 2 || !trace.Truncated || trace.Min != -4 {
+		t.Errorf("expected 2 truncated steps reaching -4, actual %+v", trace)
+	}
+}
+
+func TestTraceFrequenciesDefaultLimit(t *testing.T) {
+	trace, err := TraceFrequencies(changes("+1000000000", "-999999999"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trace.Steps) != DefaultMaxTraceSteps || !trace.Truncated || trace.Repeat == nil {
+		t.Errorf("expected %d truncated steps before a repeat, actual %d", DefaultMaxTraceSteps, len(trace.Steps))
+	}
+}
+
+func TestRealInput(t *testing.T) {
+	input := testutil.CachedInput(t, 1)
+	for _, solver := range []testutil.Solver{Part1, Part2} {
+		if _, err := solver(input); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package day01
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// A Trace records how the frequency changes, step by step, from the start up
+// to and including its first repeat. If the frequency never repeats, it covers
+// a single pass through the list of changes.
+type Trace struct {
+	Steps []TraceStep `json:"steps"`
+	// Min and Max are the lowest and highest frequencies reached in the trace,
+	// including the starting frequency of 0.
+	Min int `json:"min"`
+	Max int `json:"max"`
+	// Drift is the change in frequency over each full pass through the list.
+	Drift int `json:"drift"`
+	// Repeat is nil if no frequency is ever repeated.
+	Repeat *Repeat `json:"repeat"`
+	// StepsBeforeRepeat is the number of changes applied before the one that
+	// produced the first repeat, or -1 if there is no repeat.
+	StepsBeforeRepeat int `json:"stepsBeforeRepeat"`
+	// Truncated is set if the trace was cut short by its step limit.
+	Truncated bool `json:"truncated"`
+}
+
+// A TraceStep is the result of applying a single change.
+type TraceStep struct {
+	Step      int `json:"step"`
+	Pass      int `json:"pass"`
+	Index     int `json:"index"`
+	Change    int `json:"change"`
+	Frequency int `json:"frequency"`
+}
+
+// DefaultMaxTraceSteps is the number of steps a trace records if no limit is
+// given. Some inputs take billions of steps to repeat, which would otherwise
+// all be held in memory.
+const DefaultMaxTraceSteps = 1000000
+
+// TraceFrequencies builds the trace for the given input. At most maxSteps
+// steps are recorded, or DefaultMaxTraceSteps if maxSteps isn't positive,
+// although the statistics other than Min and Max still describe the whole run.
+func TraceFrequencies(input string, maxSteps int) (Trace, error) {
+	diffs, err := parseInput(input)
+	if err != nil {
+		return Trace{}, err
+	}
+	if maxSteps <= 0 {
+		maxSteps = DefaultMaxTraceSteps
+	}
+	trace := Trace{StepsBeforeRepeat: -1}
+	for _, diff := range diffs {
+		trace.Drift += diff
+	}
+
+	stepCount := len(diffs)
+	repeat, err := firstRepeat(diffs)
+	if err == nil {
+		trace.Repeat = &repeat
+		stepCount = repeat.steps(len(diffs))
+		trace.StepsBeforeRepeat = stepCount - 1
+	} else if _, neverRepeats := err.(*NeverRepeatsError); !neverRepeats {
+		return Trace{}, err
+	}
+	if stepCount > maxSteps {
+		stepCount = maxSteps
+		trace.Truncated = true
+	}
+
+	trace.Steps = make([]TraceStep, stepCount)
+	frequency := 0
+	for step := range trace.Steps {
+		index := step % len(diffs)
+		frequency += diffs[index]
+		trace.Steps[step] = TraceStep{
+			Step:      step + 1,
+			Pass:      step / len(diffs),
+			Index:     index,
+			Change:    diffs[index],
+			Frequency: frequency,
+		}
+		if frequency < trace.Min {
+			trace.Min = frequency
+		}
+		if frequency > trace.Max {
+			trace.Max = frequency
+		}
+	}
+	return trace, nil
+}
+
+// WriteJSON writes the whole trace, including its statistics, as JSON.
+func (t Trace) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(t)
+}
+
+// WriteCSV writes one row per step, with a header row.
+func (t Trace) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"step", "pass", "index", "change", "frequency"})
+	for _, step := range t.Steps {
+		writer.Write([]string{
+			strconv.Itoa(step.Step),
+			strconv.Itoa(step.Pass),
+			strconv.Itoa(step.Index),
+			strconv.Itoa(step.Change),
+			strconv.Itoa(step.Frequency),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
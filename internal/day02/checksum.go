@@ -0,0 +1,84 @@
+package day02
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/orn688/advent-of-code-2018/internal/util"
+)
+
+// Checksum returns the product, over each of the given multiplicities, of the
+// number of box IDs containing some letter exactly that many times. The
+// checksum for Part1 uses the multiplicities 2 and 3.
+func Checksum(input string, multiplicities []int) int {
+	counts := multiplicityCounts(parseInput(input), multiplicities)
+	checksum := 1
+	for _, multiplicity := range multiplicities {
+		checksum *= counts[multiplicity]
+	}
+	return checksum
+}
+
+// multiplicityCounts maps each multiplicity to the number of box IDs with some
+// letter that appears exactly that many times.
+func multiplicityCounts(boxIDs []string, multiplicities []int) map[int]int {
+	counts := make(map[int]int, len(multiplicities))
+	for _, boxID := range boxIDs {
+		present := make(map[int]bool)
+		for _, count := range util.LetterCounts(boxID) {
+			present[count] = true
+		}
+		for _, multiplicity := range multiplicities {
+			if present[multiplicity] {
+				counts[multiplicity]++
+			}
+		}
+	}
+	return counts
+}
+
+// A FrequencyReport describes which letters repeat within a single box ID.
+type FrequencyReport struct {
+	ID string `json:"id"`
+	// Repeated lists the letters appearing more than once, most frequent
+	// first and then alphabetically.
+	Repeated []LetterFrequency `json:"repeated"`
+}
+
+// A LetterFrequency is the number of times a letter appears in a box ID.
+type LetterFrequency struct {
+	Letter string `json:"letter"`
+	Count  int    `json:"count"`
+}
+
+// String formats the report as, for example, "bababc: b×3 a×2".
+func (r FrequencyReport) String() string {
+	parts := []string{r.ID + ":"}
+	for _, freq := range r.Repeated {
+		parts = append(parts, freq.Letter+"×"+strconv.Itoa(freq.Count))
+	}
+	return strings.Join(parts, " ")
+}
+
+// FrequencyReports returns a report for each box ID in the input.
+func FrequencyReports(input string) []FrequencyReport {
+	boxIDs := parseInput(input)
+	reports := make([]FrequencyReport, len(boxIDs))
+	for i, boxID := range boxIDs {
+		repeated := []LetterFrequency{}
+		for letter, count := range util.LetterCounts(boxID) {
+			if count > 1 {
+				repeated = append(repeated, LetterFrequency{string(letter), count})
+			}
+		}
+		sort.Slice(repeated, func(a, b int) bool {
+			if repeated[a].Count != repeated[b].Count {
+				return repeated[a].Count > repeated[b].Count
+			}
+			return repeated[a].Letter < repeated[b].Letter
+		})
+		reports[i] = FrequencyReport{ID: boxID, Repeated: repeated}
+	}
+	return reports
+}
@@ -0,0 +1,112 @@
+package day02
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orn688/advent-of-code-2018/internal/testutil"
+)
+
+const checksumInput = "abcdef\nbababc\nabbcde\nabcccd\naabcdd\nabcdee\nababab"
+
+func TestPart1(t *testing.T) {
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Name: "example", Input: checksumInput, Expected: "12"},
+	})
+}
+
+func TestChecksum(t *testing.T) {
+	testcases := []struct {
+		multiplicities []int
+		expected       int
+	}{
+		{[]int{2}, 4},
+		{[]int{3}, 3},
+		{[]int{1, 2, 3}, 6 * 4 * 3},
+		{[]int{4}, 0},
+	}
+	for _, tc := range testcases {
+		actual := Checksum(checksumInput, tc.multiplicities)
+		if actual != tc.expected {
+			t.Errorf("%v: expected %d, actual %d", tc.multiplicities, tc.expected, actual)
+		}
+	}
+}
+
+func TestFrequencyReports(t *testing.T) {
+	reports := FrequencyReports(checksumInput)
+	expected := []string{
+		"abcdef:",
+		"bababc: b×3 a×2",
+		"abbcde: b×2",
+		"abcccd: c×3",
+		"aabcdd: a×2 d×2",
+		"abcdee: e×2",
+		"ababab: a×3 b×3",
+	}
+	for i, report := range reports {
+		if report.String() != expected[i] {
+			t.Errorf("expected %q, actual %q", expected[i], report.String())
+		}
+	}
+}
+
+func TestPart2(t *testing.T) {
+	input := "abcde\nfghij\nklmno\npqrst\nfguij\naxcye\nwvxyz"
+	testutil.RunExamples(t, Part2, []testutil.Example{
+		{Name: "example", Input: input, Expected: "fgij"},
+		{Name: "mixed lengths", Input: "abc\nxy\nabcd\nxz", Expected: "x"},
+	})
+}
+
+func TestFindNearMatches(t *testing.T) {
+	matches := FindNearMatches("héllo\nhello\nhelo\nyellow", 2)
+	expected := []Match{
+		{I: 0, J: 1, A: "héllo", B: "hello", Distance: 1,
+			Edits: []Edit{{Pos: 1, From: "é", To: "e"}}, Common: "hllo"},
+		{I: 0, J: 2, A: "héllo", B: "helo", Distance: 2,
+			Edits: []Edit{{Pos: 1, From: "é"}, {Pos: 2, From: "l", To: "e"}}, Common: "hlo"},
+		{I: 1, J: 2, A: "hello", B: "helo", Distance: 1,
+			Edits: []Edit{{Pos: 2, From: "l"}}, Common: "helo"},
+		{I: 1, J: 3, A: "hello", B: "yellow", Distance: 2,
+			Edits: []Edit{{Pos: 0, From: "h", To: "y"}, {Pos: 5, To: "w"}}, Common: "ello"},
+	}
+	if !reflect.DeepEqual(expected, matches) {
+		t.Errorf("expected %+v, actual %+v", expected, matches)
+	}
+}
+
+func TestNearMatchesAgainstBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	alphabet := []rune("abcé")
+	for trial := 0; trial < 50; trial++ {
+		ids := make([]string, 30)
+		for i := range ids {
+			id := make([]rune, 2+random.Intn(4))
+			for c := range id {
+				id[c] = alphabet[random.Intn(len(alphabet))]
+			}
+			ids[i] = string(id)
+		}
+		for k := 0; k <= 3; k++ {
+			expected := []string{}
+			for i := range ids {
+				for j := i + 1; j < len(ids); j++ {
+					if editDistance([]rune(ids[i]), []rune(ids[j])) <= k {
+						expected = append(expected, ids[i]+"/"+ids[j])
+					}
+				}
+			}
+			actual := []string{}
+			for _, match := range nearMatches(ids, k) {
+				actual = append(actual, match.A+"/"+match.B)
+			}
+			if !reflect.DeepEqual(expected, actual) {
+				t.Fatalf("k=%d, ids %s:\nexpected %v\nactual   %v",
+					k, strings.Join(ids, ","), expected, actual)
+			}
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package day02
+
+import "sort"
+
+// A Match is a pair of box IDs within some edit distance of each other.
+type Match struct {
+	// I and J are the positions of the two IDs in the input, with I < J.
+	I        int    `json:"i"`
+	J        int    `json:"j"`
+	A        string `json:"a"`
+	B        string `json:"b"`
+	Distance int    `json:"distance"`
+	// Edits turn A into B. There are exactly Distance of them.
+	Edits []Edit `json:"edits"`
+	// Common holds the characters of A that are left untouched by the edits,
+	// i.e. the letters that the two IDs share.
+	Common string `json:"common"`
+}
+
+// An Edit is a single-character change to a box ID. Pos is the position (in
+// characters, not bytes) within the first ID of the character being changed,
+// or, for an insertion, of the character that the new one goes before.
+type Edit struct {
+	Pos  int    `json:"pos"`
+	From string `json:"from,omitempty"` // empty for an insertion
+	To   string `json:"to,omitempty"`   // empty for a deletion
+}
+
+func (e Edit) isSubstitution() bool {
+	return e.From != "" && e.To != ""
+}
+
+// FindNearMatches returns every pair of box IDs in the input whose
+// (Levenshtein) edit distance is at most k, ordered by their positions in the
+// input. IDs may have different lengths and contain any Unicode characters.
+func FindNearMatches(input string, k int) []Match {
+	return nearMatches(parseInput(input), k)
+}
+
+func nearMatches(boxIDs []string, k int) []Match {
+	ids := make([][]rune, len(boxIDs))
+	for i, boxID := range boxIDs {
+		ids[i] = []rune(boxID)
+	}
+
+	var pairs [][2]int
+	if k == 1 {
+		pairs = deletionNeighborPairs(ids)
+	} else {
+		pairs = bkTreePairs(ids, k)
+	}
+	sort.Slice(pairs, func(a, b int) bool {
+		if pairs[a][0] != pairs[b][0] {
+			return pairs[a][0] < pairs[b][0]
+		}
+		return pairs[a][1] < pairs[b][1]
+	})
+
+	matches := []Match{}
+	for _, pair := range pairs {
+		i, j := pair[0], pair[1]
+		edits, common := align(ids[i], ids[j])
+		if len(edits) > k {
+			// A candidate that only shared a neighbor with the other ID.
+			continue
+		}
+		matches = append(matches, Match{
+			I:        i,
+			J:        j,
+			A:        boxIDs[i],
+			B:        boxIDs[j],
+			Distance: len(edits),
+			Edits:    edits,
+			Common:   common,
+		})
+	}
+	return matches
+}
+
+// deletionNeighborPairs finds candidate pairs within distance 1 by indexing
+// each ID under itself and every string formed by deleting one of its
+// characters: two IDs can only be within distance 1 if they share one of
+// those keys. Some candidates are further apart, so they must be checked.
+func deletionNeighborPairs(ids [][]rune) [][2]int {
+	buckets := make(map[string][]int)
+	for i, id := range ids {
+		keys := map[string]bool{string(id): true}
+		for pos := range id {
+			deleted := make([]rune, 0, len(id)-1)
+			deleted = append(deleted, id[:pos]...)
+			deleted = append(deleted, id[pos+1:]...)
+			keys[string(deleted)] = true
+		}
+		for key := range keys {
+			buckets[key] = append(buckets[key], i)
+		}
+	}
+
+	seen := make(map[[2]int]bool)
+	pairs := [][2]int{}
+	for _, bucket := range buckets {
+		for a := 0; a < len(bucket); a++ {
+			for b := a + 1; b < len(bucket); b++ {
+				pair := [2]int{bucket[a], bucket[b]}
+				if pair[0] > pair[1] {
+					pair[0], pair[1] = pair[1], pair[0]
+				}
+				if !seen[pair] {
+					seen[pair] = true
+					pairs = append(pairs, pair)
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+// A bkNode is a node in a BK-tree, which indexes strings by edit distance.
+// Every descendant of children[d] is at distance d from the node's ID, so by
+// the triangle inequality a search for IDs within k of some target only needs
+// to visit the children with d within k of the node's own distance.
+type bkNode struct {
+	index    int
+	children map[int]*bkNode
+}
+
+func bkTreePairs(ids [][]rune, k int) [][2]int {
+	pairs := [][2]int{}
+	if len(ids) == 0 {
+		return pairs
+	}
+	root := &bkNode{index: 0, children: make(map[int]*bkNode)}
+	for i := 1; i < len(ids); i++ {
+		// Only earlier IDs are in the tree, so each pair is found once.
+		for _, j := range root.search(ids, ids[i], k) {
+			pairs = append(pairs, [2]int{j, i})
+		}
+		root.insert(ids, i)
+	}
+	return pairs
+}
+
+func (node *bkNode) insert(ids [][]rune, index int) {
+	for {
+		dist := editDistance(ids[node.index], ids[index])
+		child, present := node.children[dist]
+		if !present {
+			node.children[dist] = &bkNode{index: index, children: make(map[int]*bkNode)}
+			return
+		}
+		node = child
+	}
+}
+
+func (node *bkNode) search(ids [][]rune, target []rune, k int) []int {
+	found := []int{}
+	stack := []*bkNode{node}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		dist := editDistance(ids[current.index], target)
+		if dist <= k {
+			found = append(found, current.index)
+		}
+		for childDist, child := range current.children {
+			if dist-k <= childDist && childDist <= dist+k {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return found
+}
+
+func editDistance(a, b []rune) int {
+	table := editTable(a, b)
+	return table[len(a)][len(b)]
+}
+
+// editTable returns the table of edit distances between every prefix of a and
+// every prefix of b.
+func editTable(a, b []rune) [][]int {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+		table[i][0] = i
+	}
+	for j := range table[0] {
+		table[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			substitution := table[i-1][j-1]
+			if a[i-1] != b[j-1] {
+				substitution++
+			}
+			table[i][j] = minOf(substitution, table[i-1][j]+1, table[i][j-1]+1)
+		}
+	}
+	return table
+}
+
+// align returns a shortest list of edits turning a into b, along with the
+// characters of a that the edits leave alone.
+func align(a, b []rune) ([]Edit, string) {
+	table := editTable(a, b)
+	edits := []Edit{}
+	common := []rune{}
+	i, j := len(a), len(b)
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && a[i-1] == b[j-1] && table[i][j] == table[i-1][j-1]:
+			common = append(common, a[i-1])
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && table[i][j] == table[i-1][j-1]+1:
+			edits = append(edits, Edit{Pos: i - 1, From: string(a[i-1]), To: string(b[j-1])})
+			i, j = i-1, j-1
+		case i > 0 && table[i][j] == table[i-1][j]+1:
+			edits = append(edits, Edit{Pos: i - 1, From: string(a[i-1])})
+			i--
+		default:
+			edits = append(edits, Edit{Pos: i, To: string(b[j-1])})
+			j--
+		}
+	}
+	// Both were built back to front.
+	for l, r := 0, len(edits)-1; l < r; l, r = l+1, r-1 {
+		edits[l], edits[r] = edits[r], edits[l]
+	}
+	for l, r := 0, len(common)-1; l < r; l, r = l+1, r-1 {
+		common[l], common[r] = common[r], common[l]
+	}
+	return edits, string(common)
+}
+
+func minOf(first int, rest ...int) int {
+	result := first
+	for _, x := range rest {
+		if x < result {
+			result = x
+		}
+	}
+	return result
+}
@@ -7,14 +7,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/orn688/advent-of-code-2018/internal/geom"
 	"github.com/orn688/advent-of-code-2018/internal/util"
 )
 
-type coordinate struct {
-	X int
-	Y int
-}
-
 type fabricClaim struct {
 	ID       int
 	LeftDist int
@@ -23,18 +19,12 @@ type fabricClaim struct {
 	Height   int
 }
 
-func (c fabricClaim) allCoordinates() []coordinate {
-	coordinates := make([]coordinate, c.Width*c.Height)
-
-	for yOffset := 0; yOffset < c.Height; yOffset++ {
-		for xOffset := 0; xOffset < c.Width; xOffset++ {
-			x, y := xOffset+c.LeftDist, yOffset+c.TopDist
-			index := xOffset + (yOffset * c.Width)
-			coordinates[index] = coordinate{x, y}
-		}
-	}
+func (c fabricClaim) rect() geom.Rect {
+	return geom.RectFromSize(c.LeftDist, c.TopDist, c.Width, c.Height)
+}
 
-	return coordinates
+func (c fabricClaim) allCoordinates() []geom.Point2 {
+	return c.rect().Points()
 }
 
 // Part1 returns the number of disputed squares (>= 2 claims).
@@ -43,17 +33,7 @@ func Part1(input string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-
-	claimCounts := getClaimCounts(claims)
-
-	numDisputedSquares := 0
-	for _, count := range claimCounts {
-		if count > 1 {
-			numDisputedSquares++
-		}
-	}
-
-	return strconv.Itoa(numDisputedSquares), nil
+	return strconv.Itoa(overlapArea(claims)), nil
 }
 
 // Part2 returns the ID of the only claim that doesn't overlap with any others.
@@ -62,24 +42,11 @@ func Part2(input string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-
-	claimCounts := getClaimCounts(claims)
-
-	for _, claim := range claims {
-		conflict := false
-		for _, coord := range claim.allCoordinates() {
-			if claimCounts[coord] > 1 {
-				conflict = true
-				break
-			}
-		}
-
-		if !conflict {
-			return strconv.Itoa(claim.ID), nil
-		}
+	intact := intactClaims(claims)
+	if len(intact) == 0 {
+		return "", errors.New("no matching claim in input")
 	}
-
-	return "", errors.New("no matching claim in input")
+	return strconv.Itoa(intact[0]), nil
 }
 
 func parseInput(input string) ([]*fabricClaim, error) {
@@ -115,8 +82,8 @@ func parseInput(input string) ([]*fabricClaim, error) {
 	return claims, nil
 }
 
-func getClaimCounts(claims []*fabricClaim) map[coordinate]int {
-	claimCounts := make(map[coordinate]int)
+func getClaimCounts(claims []*fabricClaim) map[geom.Point2]int {
+	claimCounts := make(map[geom.Point2]int)
 
 	for _, claim := range claims {
 		for _, coord := range claim.allCoordinates() {
@@ -126,3 +93,35 @@ func getClaimCounts(claims []*fabricClaim) map[coordinate]int {
 
 	return claimCounts
 }
+
+// overlapAreaByMap is the straightforward version of overlapArea, which counts
+// the claims on every square. Its memory use grows with the total area of the
+// claims, so it's kept only to cross-check the sweep.
+func overlapAreaByMap(claims []*fabricClaim) int {
+	numDisputedSquares := 0
+	for _, count := range getClaimCounts(claims) {
+		if count > 1 {
+			numDisputedSquares++
+		}
+	}
+	return numDisputedSquares
+}
+
+// intactClaimsByMap is the straightforward version of intactClaims.
+func intactClaimsByMap(claims []*fabricClaim) []int {
+	claimCounts := getClaimCounts(claims)
+	ids := []int{}
+	for _, claim := range claims {
+		conflict := false
+		for _, coord := range claim.allCoordinates() {
+			if claimCounts[coord] > 1 {
+				conflict = true
+				break
+			}
+		}
+		if !conflict {
+			ids = append(ids, claim.ID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,167 @@
+package day03
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orn688/advent-of-code-2018/internal/testutil"
+)
+
+const input = `
+#1 @ 1,3: 4x4
+#2 @ 3,1: 4x4
+#3 @ 5,5: 2x2
+`
+
+func TestPart1(t *testing.T) {
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Name: "example", Input: input, Expected: "4"},
+	})
+}
+
+func TestPart2(t *testing.T) {
+	testutil.RunExamples(t, Part2, []testutil.Example{
+		{Name: "example", Input: input, Expected: "3"},
+	})
+}
+
+// randomClaims generates count claims on a size x size piece of fabric.
+func randomClaims(random *rand.Rand, count, size, maxSide int) string {
+	lines := make([]string, count)
+	for i := range lines {
+		width, height := 1+random.Intn(maxSide), 1+random.Intn(maxSide)
+		left, top := random.Intn(size-width+1), random.Intn(size-height+1)
+		lines[i] = fmt.Sprintf("#%d @ %d,%d: %dx%d", i+1, left, top, width, height)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestSweepMatchesMap(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		count := 1 + random.Intn(40)
+		claims, err := parseInput(randomClaims(random, count, 30, 12))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := overlapAreaByMap(claims), overlapArea(claims); actual != expected {
+			t.Errorf("trial %d: expected overlap %d, actual %d", trial, expected, actual)
+		}
+		if expected, actual := intactClaimsByMap(claims), intactClaims(claims); !reflect.DeepEqual(expected, actual) {
+			t.Errorf("trial %d: expected intact %v, actual %v", trial, expected, actual)
+		}
+	}
+}
+
+func TestSweepMatchesMapOnLargeInput(t *testing.T) {
+	claims, err := parseInput(randomClaims(rand.New(rand.NewSource(2)), 1300, 1000, 30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := overlapAreaByMap(claims), overlapArea(claims); actual != expected {
+		t.Errorf("expected overlap %d, actual %d", expected, actual)
+	}
+	if expected, actual := intactClaimsByMap(claims), intactClaims(claims); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected intact %v, actual %v", expected, actual)
+	}
+}
+
+func BenchmarkOverlapArea(b *testing.B) {
+	claims, _ := parseInput(randomClaims(rand.New(rand.NewSource(1)), 1300, 1000, 30))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		overlapArea(claims)
+	}
+}
+
+func BenchmarkOverlapAreaByMap(b *testing.B) {
+	claims, _ := parseInput(randomClaims(rand.New(rand.NewSource(1)), 1300, 1000, 30))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		overlapAreaByMap(claims)
+	}
+}
+
+func TestFabricQueries(t *testing.T) {
+	fabric, err := NewFabric(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := []int{1, 2}, fabric.ClaimsCovering(4, 4); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+	if expected, actual := []int{}, fabric.ClaimsCovering(0, 0); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+	if conflicts, err := fabric.Conflicts(2); err != nil || !reflect.DeepEqual(conflicts, []int{1}) {
+		t.Errorf("expected [1], actual %v (%v)", conflicts, err)
+	}
+	if _, err := fabric.Conflicts(4); err == nil {
+		t.Errorf("expected an error for a missing claim")
+	}
+	expected := map[int][]int{1: {2}, 2: {1}, 3: {}}
+	if actual := fabric.ConflictGraph(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+}
+
+func TestOverlapColor(t *testing.T) {
+	light, dark := color.RGBA{0xf0, 0x90, 0x90, 0xff}, color.RGBA{0x80, 0x00, 0x00, 0xff}
+	for _, tc := range []struct {
+		count, maxCount int
+		expected        color.RGBA
+	}{
+		{2, 5, light},
+		{5, 5, dark},
+		// Double claims are the most contested when there's nothing more.
+		{2, 2, dark},
+	} {
+		if actual := overlapColor(tc.count, tc.maxCount); actual != tc.expected {
+			t.Errorf("%d of %d: expected %v, actual %v", tc.count, tc.maxCount, tc.expected, actual)
+		}
+	}
+}
+
+func TestImage(t *testing.T) {
+	fabric, err := NewFabric(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := fabric.Image()
+	if size := img.Bounds().Size(); size.X != 1000 || size.Y != 1000 {
+		t.Errorf("expected a 1000x1000 image, actual %v", size)
+	}
+	testcases := []struct {
+		x, y     int
+		expected color.RGBA
+	}{
+		{0, 0, backgroundColor},
+		{1, 3, claimColor},
+		{4, 4, overlapColor(2, 2)},
+		{6, 6, intactColor},
+		{7, 7, backgroundColor},
+	}
+	for _, tc := range testcases {
+		if actual := color.RGBAModel.Convert(img.At(tc.x, tc.y)); actual != tc.expected {
+			t.Errorf("(%d,%d): expected %v, actual %v", tc.x, tc.y, tc.expected, actual)
+		}
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	fabric, err := NewFabric(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := fabric.WriteSVG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertGolden(t, "example.svg", buf.Bytes())
+	testutil.AssertWriteErrors(t, fabric.WriteSVG)
+}
@@ -0,0 +1,85 @@
+package day03
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/orn688/advent-of-code-2018/internal/geom"
+)
+
+// A Fabric holds all of the claims from an input so that questions can be
+// asked about how they're laid out.
+type Fabric struct {
+	claims []*fabricClaim
+	// graph maps the index of each claim to the indexes of the claims that it
+	// overlaps.
+	graph map[int][]int
+	// indexByID maps claim IDs to their index in claims.
+	indexByID map[int]int
+}
+
+// NewFabric parses the claims in the input and finds where they conflict.
+func NewFabric(input string) (*Fabric, error) {
+	claims, err := parseInput(input)
+	if err != nil {
+		return nil, err
+	}
+	fabric := &Fabric{
+		claims:    claims,
+		graph:     make(map[int][]int, len(claims)),
+		indexByID: make(map[int]int, len(claims)),
+	}
+	for i, claim := range claims {
+		if _, duplicate := fabric.indexByID[claim.ID]; duplicate {
+			return nil, fmt.Errorf("duplicate claim #%d", claim.ID)
+		}
+		fabric.indexByID[claim.ID] = i
+	}
+	for _, pair := range conflictingPairs(claims) {
+		fabric.graph[pair[0]] = append(fabric.graph[pair[0]], pair[1])
+		fabric.graph[pair[1]] = append(fabric.graph[pair[1]], pair[0])
+	}
+	return fabric, nil
+}
+
+// ClaimsCovering returns the IDs of the claims that include the square at
+// (x, y), in input order.
+func (f *Fabric) ClaimsCovering(x, y int) []int {
+	ids := []int{}
+	for _, claim := range f.claims {
+		if claim.rect().Contains(geom.Point2{X: x, Y: y}) {
+			ids = append(ids, claim.ID)
+		}
+	}
+	return ids
+}
+
+// Conflicts returns the IDs of the claims that overlap the claim with the
+// given ID, in ascending order.
+func (f *Fabric) Conflicts(id int) ([]int, error) {
+	index, present := f.indexByID[id]
+	if !present {
+		return nil, fmt.Errorf("no claim #%d", id)
+	}
+	return f.claimIDs(f.graph[index]), nil
+}
+
+// ConflictGraph returns an adjacency list mapping every claim ID to the IDs of
+// the claims that it overlaps, in ascending order. Claims without conflicts
+// map to an empty list.
+func (f *Fabric) ConflictGraph() map[int][]int {
+	graph := make(map[int][]int, len(f.claims))
+	for i, claim := range f.claims {
+		graph[claim.ID] = f.claimIDs(f.graph[i])
+	}
+	return graph
+}
+
+func (f *Fabric) claimIDs(indexes []int) []int {
+	ids := make([]int, len(indexes))
+	for i, index := range indexes {
+		ids[i] = f.claims[index].ID
+	}
+	sort.Ints(ids)
+	return ids
+}
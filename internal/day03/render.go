@@ -0,0 +1,161 @@
+package day03
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"github.com/orn688/advent-of-code-2018/internal/geom"
+)
+
+// The fabric is at least this many inches on each side.
+const fabricSize = 1000
+
+var (
+	backgroundColor = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	claimColor      = color.RGBA{0x9e, 0xc5, 0xe8, 0xff}
+	intactColor     = color.RGBA{0x2c, 0xa0, 0x2c, 0xff}
+	// Claims in SVGs are translucent, so overlaps darken as they stack up.
+	svgClaimColor = color.RGBA{0xd6, 0x27, 0x28, 0xff}
+)
+
+// overlapColor shades a square claimed count (>= 2) times, from light red for
+// a double claim up to dark red for the most contested squares. If no square
+// is claimed more than twice, the double claims are the most contested, so
+// they're all dark red.
+func overlapColor(count, maxCount int) color.RGBA {
+	// 0 for a double claim, up to 1 for the most claimed square, which wins
+	// when they're the same.
+	intensity := 1.0
+	if maxCount > 2 {
+		intensity = float64(count-2) / float64(maxCount-2)
+	}
+	return color.RGBA{
+		R: uint8(0xf0 - 0x70*intensity),
+		G: uint8(0x90 - 0x90*intensity),
+		B: uint8(0x90 - 0x90*intensity),
+		A: 0xff,
+	}
+}
+
+// canvas returns the area to draw: the 1000x1000 fabric, grown if any claims
+// hang off of it.
+func (f *Fabric) canvas() geom.Rect {
+	canvas := geom.RectFromSize(0, 0, fabricSize, fabricSize)
+	for _, claim := range f.claims {
+		canvas = canvas.Union(claim.rect())
+	}
+	return canvas
+}
+
+// coverage returns the number of claims covering each square of the canvas,
+// indexed as [y][x] relative to the canvas, along with the largest count.
+func (f *Fabric) coverage(canvas geom.Rect) ([][]int, int) {
+	// Mark the corners of each claim in a difference table, then take the 2D
+	// running sum.
+	counts := make([][]int, canvas.Height()+1)
+	for y := range counts {
+		counts[y] = make([]int, canvas.Width()+1)
+	}
+	for _, claim := range f.claims {
+		rect := claim.rect()
+		if rect.Empty() {
+			continue
+		}
+		lo, hi := rect.Min.Sub(canvas.Min), rect.Max.Sub(canvas.Min)
+		counts[lo.Y][lo.X]++
+		counts[lo.Y][hi.X]--
+		counts[hi.Y][lo.X]--
+		counts[hi.Y][hi.X]++
+	}
+	maxCount := 0
+	for y := range counts {
+		for x := range counts[y] {
+			if x > 0 {
+				counts[y][x] += counts[y][x-1]
+			}
+			if y > 0 {
+				counts[y][x] += counts[y-1][x]
+			}
+			if x > 0 && y > 0 {
+				counts[y][x] -= counts[y-1][x-1]
+			}
+			if counts[y][x] > maxCount {
+				maxCount = counts[y][x]
+			}
+		}
+	}
+	return counts, maxCount
+}
+
+// Image draws the fabric with one pixel per square inch. Claimed squares are
+// blue, overlapping squares are shaded red by the number of claims, and
+// claims without any conflicts are green.
+func (f *Fabric) Image() image.Image {
+	canvas := f.canvas()
+	counts, maxCount := f.coverage(canvas)
+	img := image.NewRGBA(image.Rect(0, 0, canvas.Width(), canvas.Height()))
+	for y := 0; y < canvas.Height(); y++ {
+		for x := 0; x < canvas.Width(); x++ {
+			switch count := counts[y][x]; {
+			case count == 0:
+				img.SetRGBA(x, y, backgroundColor)
+			case count == 1:
+				img.SetRGBA(x, y, claimColor)
+			default:
+				img.SetRGBA(x, y, overlapColor(count, maxCount))
+			}
+		}
+	}
+	for i, claim := range f.claims {
+		if len(f.graph[i]) > 0 {
+			continue
+		}
+		for _, pt := range claim.allCoordinates() {
+			pt = pt.Sub(canvas.Min)
+			img.SetRGBA(pt.X, pt.Y, intactColor)
+		}
+	}
+	return img
+}
+
+// WritePNG writes the fabric drawn by Image as a PNG.
+func (f *Fabric) WritePNG(w io.Writer) error {
+	return png.Encode(w, f.Image())
+}
+
+// WriteSVG draws the fabric as an SVG, with one semi-transparent rectangle
+// per claim so that overlaps get darker the more claims they have. Claims
+// without any conflicts are outlined in green, and hovering over a claim
+// shows its ID.
+func (f *Fabric) WriteSVG(w io.Writer) error {
+	canvas := f.canvas()
+	if _, err := fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"%d %d %d %d\">\n",
+		canvas.Min.X, canvas.Min.Y, canvas.Width(), canvas.Height()); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+		canvas.Min.X, canvas.Min.Y, canvas.Width(), canvas.Height(), hexColor(backgroundColor)); err != nil {
+		return err
+	}
+	for i, claim := range f.claims {
+		fill, style := hexColor(svgClaimColor), `fill-opacity="0.3"`
+		if len(f.graph[i]) == 0 {
+			fill = hexColor(intactColor)
+			style = fmt.Sprintf(`stroke="%s" stroke-width="1"`, hexColor(intactColor))
+		}
+		rect := claim.rect()
+		if _, err := fmt.Fprintf(w, "  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\" %s><title>#%d</title></rect>\n",
+			rect.Min.X, rect.Min.Y, rect.Width(), rect.Height(), fill, style, claim.ID); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "</svg>")
+	return err
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,193 @@
+package day03
+
+import "sort"
+
+// A sweepEvent is the left (start) or right (end) edge of a claim, met as a
+// vertical line sweeps across the fabric from left to right.
+type sweepEvent struct {
+	x     int
+	claim int // index into the claims
+	start bool
+}
+
+// sweepEvents returns the edges of all claims, ordered by x. At the same x,
+// ends come before starts because claims that only touch don't overlap.
+func sweepEvents(claims []*fabricClaim) []sweepEvent {
+	events := make([]sweepEvent, 0, 2*len(claims))
+	for i, claim := range claims {
+		rect := claim.rect()
+		if rect.Empty() {
+			continue
+		}
+		events = append(events,
+			sweepEvent{rect.Min.X, i, true},
+			sweepEvent{rect.Max.X, i, false})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].x != events[j].x {
+			return events[i].x < events[j].x
+		}
+		return !events[i].start && events[j].start
+	})
+	return events
+}
+
+// overlapArea returns the number of squares covered by two or more claims in
+// O(n log n) time for n claims, without visiting individual squares.
+//
+// Between consecutive edges the set of claims crossed by the sweep line
+// doesn't change, so the overlap in that strip is its width times the length
+// of the line covered at least twice. That length is maintained by a segment
+// tree over the (compressed) y coordinates of the claims' edges.
+func overlapArea(claims []*fabricClaim) int {
+	ys := []int{}
+	for _, claim := range claims {
+		ys = append(ys, claim.rect().Min.Y, claim.rect().Max.Y)
+	}
+	ys = sortedUnique(ys)
+	tree := newCoverageTree(ys)
+
+	area := 0
+	lastX := 0
+	for _, event := range sweepEvents(claims) {
+		area += tree.doubleCovered() * (event.x - lastX)
+		lastX = event.x
+		rect := claims[event.claim].rect()
+		delta := 1
+		if !event.start {
+			delta = -1
+		}
+		tree.add(rect.Min.Y, rect.Max.Y, delta)
+	}
+	return area
+}
+
+// intactClaims returns the IDs of the claims that don't overlap any other
+// claim, in input order.
+func intactClaims(claims []*fabricClaim) []int {
+	conflicts := make([]bool, len(claims))
+	for _, pair := range conflictingPairs(claims) {
+		conflicts[pair[0]] = true
+		conflicts[pair[1]] = true
+	}
+	ids := []int{}
+	for i, claim := range claims {
+		if !conflicts[i] {
+			ids = append(ids, claim.ID)
+		}
+	}
+	return ids
+}
+
+// conflictingPairs returns the indexes of every pair of overlapping claims.
+//
+// It sweeps across the fabric the same way as overlapArea, comparing each
+// claim only with the claims that the sweep line crosses when it starts. That
+// takes O(n log n + n·a) time, where a is the largest number of claims
+// crossing any column, rather than comparing every pair of claims.
+func conflictingPairs(claims []*fabricClaim) [][2]int {
+	pairs := [][2]int{}
+	active := make(map[int]bool)
+	for _, event := range sweepEvents(claims) {
+		if !event.start {
+			delete(active, event.claim)
+			continue
+		}
+		rect := claims[event.claim].rect()
+		for other := range active {
+			otherRect := claims[other].rect()
+			if rect.Min.Y < otherRect.Max.Y && otherRect.Min.Y < rect.Max.Y {
+				pairs = append(pairs, [2]int{other, event.claim})
+			}
+		}
+		active[event.claim] = true
+	}
+	return pairs
+}
+
+// A coverageTree is a segment tree over the gaps between sorted y
+// coordinates. It tracks how many times each gap is covered by the intervals
+// added to it, and how much of the line is covered at least twice.
+type coverageTree struct {
+	ys []int
+	// For each node: the number of intervals covering the node's entire
+	// range without covering its parent's, and the lengths of the node's range
+	// covered at least once and at least twice.
+	count, once, twice []int
+}
+
+func newCoverageTree(ys []int) *coverageTree {
+	size := 4 * len(ys)
+	return &coverageTree{
+		ys:    ys,
+		count: make([]int, size),
+		once:  make([]int, size),
+		twice: make([]int, size),
+	}
+}
+
+// add changes the coverage of the half-open range [lo, hi) by delta. lo and hi
+// must be among the tree's y coordinates.
+func (tree *coverageTree) add(lo, hi, delta int) {
+	if len(tree.ys) < 2 {
+		return
+	}
+	loIndex := sort.SearchInts(tree.ys, lo)
+	hiIndex := sort.SearchInts(tree.ys, hi)
+	tree.update(1, 0, len(tree.ys)-1, loIndex, hiIndex, delta)
+}
+
+// doubleCovered returns the length of the line covered at least twice.
+func (tree *coverageTree) doubleCovered() int {
+	if len(tree.ys) < 2 {
+		return 0
+	}
+	return tree.twice[1]
+}
+
+// update applies delta to [lo, hi) within the node covering [left, right),
+// where all bounds are indexes into ys.
+func (tree *coverageTree) update(node, left, right, lo, hi, delta int) {
+	if hi <= left || right <= lo {
+		return
+	}
+	if lo <= left && right <= hi {
+		tree.count[node] += delta
+	} else {
+		mid := (left + right) / 2
+		tree.update(2*node, left, mid, lo, hi, delta)
+		tree.update(2*node+1, mid, right, lo, hi, delta)
+	}
+	tree.pull(node, left, right)
+}
+
+// pull recomputes a node's covered lengths from its count and its children.
+func (tree *coverageTree) pull(node, left, right int) {
+	length := tree.ys[right] - tree.ys[left]
+	leaf := right-left == 1
+	childOnce, childTwice := 0, 0
+	if !leaf {
+		childOnce = tree.once[2*node] + tree.once[2*node+1]
+		childTwice = tree.twice[2*node] + tree.twice[2*node+1]
+	}
+	switch {
+	case tree.count[node] >= 2:
+		tree.once[node], tree.twice[node] = length, length
+	case tree.count[node] == 1:
+		// Anything covered by a child is now covered twice.
+		tree.once[node], tree.twice[node] = length, childOnce
+	default:
+		tree.once[node], tree.twice[node] = childOnce, childTwice
+	}
+}
+
+func sortedUnique(nums []int) []int {
+	sort.Ints(nums)
+	unique := nums[:0]
+	for i, num := range nums {
+		if i == 0 || num != nums[i-1] {
+			unique = append(unique, num)
+		}
+	}
+	return unique
+}
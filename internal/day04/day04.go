@@ -6,181 +6,297 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/orn688/advent-of-code-2018/internal/interval"
 )
 
+// Guards only nap during the midnight hour (00:00 to 00:59) as far as the
+// puzzle is concerned, so any part of a nap outside of it is ignored.
+var midnightHour = interval.Interval{Start: 0, End: 60}
+
 type guardNap struct {
 	GuardID   int
 	StartTime time.Time
 	EndTime   time.Time
 }
 
+func (nap guardNap) span() interval.TimeInterval {
+	return interval.TimeInterval{Start: nap.StartTime, End: nap.EndTime}
+}
+
+// midnightMinutes returns the minutes of the midnight hour covered by the nap,
+// on every night that it spans.
+func (nap guardNap) midnightMinutes() []interval.Interval {
+	minutes := []interval.Interval{}
+	for _, iv := range nap.span().MinutesOfDay() {
+		if clipped := iv.Intersect(midnightHour); !clipped.Empty() {
+			minutes = append(minutes, clipped)
+		}
+	}
+	return minutes
+}
+
+// A guardShift is a single guard's time on duty.
+type guardShift struct {
+	GuardID int
+	// Date is the day whose midnight hour the shift covers.
+	Date time.Time
+	Naps []guardNap
+}
+
+func newGuardShift(guardID int, start time.Time) guardShift {
+	date := start.Truncate(24 * time.Hour)
+	if start.Hour() >= 12 {
+		// An evening start, e.g. 23:58, is a shift for the next night.
+		date = date.AddDate(0, 0, 1)
+	}
+	return guardShift{GuardID: guardID, Date: date, Naps: []guardNap{}}
+}
+
+type eventKind int
+
+const (
+	beginShift eventKind = iota
+	fallAsleep
+	wakeUp
+)
+
 type guardEvent struct {
+	// GuardID is only set for beginShift events.
 	GuardID   int
 	EventTime time.Time
-	Awake     bool
+	Kind      eventKind
+	Line      string
 }
 
-const unknownGuardID = -1
+// A LogErrorKind classifies a LogError.
+type LogErrorKind int
+
+// The ways in which a log can be invalid.
+const (
+	MalformedEntry LogErrorKind = iota
+	// NoGuardOnDuty means that someone fell asleep before any shift began.
+	NoGuardOnDuty
+	// SleepWhileAsleep means that a guard fell asleep twice in a row.
+	SleepWhileAsleep
+	// WakeWithoutSleep means that a guard woke up without falling asleep.
+	WakeWithoutSleep
+	// ShiftOverlap means that a guard began their shift while the guard
+	// before them was still asleep.
+	ShiftOverlap
+	// UnfinishedNap means that the log ended with a guard asleep.
+	UnfinishedNap
+)
+
+var logErrorDescriptions = map[LogErrorKind]string{
+	MalformedEntry:   "malformed entry",
+	NoGuardOnDuty:    "no guard is on duty",
+	SleepWhileAsleep: "guard is already asleep",
+	WakeWithoutSleep: "guard wakes up without falling asleep",
+	ShiftOverlap:     "shift begins while the previous guard is asleep",
+	UnfinishedNap:    "log ends while a guard is asleep",
+}
+
+// A LogError is returned for a guard log that is malformed or that describes
+// an impossible sequence of events.
+type LogError struct {
+	Kind LogErrorKind
+	// Line is the offending entry.
+	Line string
+	// Err is the underlying parse error, if any.
+	Err error
+}
+
+func (e *LogError) Error() string {
+	msg := fmt.Sprintf("%s: %s", logErrorDescriptions[e.Kind], e.Line)
+	if e.Err != nil {
+		msg += fmt.Sprintf(" (%s)", e.Err)
+	}
+	return msg
+}
 
 // Part1 returns the id of the guard with the most time spent asleep,
-// multiplied by the minute (between 00:00 and 00:50) during which that guard
+// multiplied by the minute (between 00:00 and 00:59) during which that guard
 // was most often asleep.
 func Part1(input string) (string, error) {
-	naps, err := parseInput(input)
+	answer, _, err := Part1WithCaveats(input)
+	return answer, err
+}
+
+// Part1WithCaveats is Part1, along with any caveats about the answer.
+func Part1WithCaveats(input string) (string, []string, error) {
+	ranking, err := RankStrategy1(input)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	sleepiestGuardID := getSleepiestGuard(naps)
-	sleepiestMinute, _ := getSleepiestMinute(naps, sleepiestGuardID)
-
-	return strconv.Itoa(sleepiestGuardID * sleepiestMinute), nil
+	return strconv.Itoa(ranking.Answer()), ranking.Caveats(), nil
 }
 
 // Part2 returns the ID of the guard who is most frequently asleep at the same
 // minute, multiplied by that minute.
 func Part2(input string) (string, error) {
-	naps, err := parseInput(input)
+	answer, _, err := Part2WithCaveats(input)
+	return answer, err
+}
+
+// Part2WithCaveats is Part2, along with any caveats about the answer.
+func Part2WithCaveats(input string) (string, []string, error) {
+	ranking, err := RankStrategy2(input)
 	if err != nil {
-		return "", err
-	}
-	uniqueGuardIDs := make(map[int]bool)
-	for _, nap := range naps {
-		uniqueGuardIDs[nap.GuardID] = true
-	}
-	resultGuardID := -1
-	resultMinute := -1
-	resultDaysAsleepAtMinute := 0
-	for guardID := range uniqueGuardIDs {
-		sleepiestMinute, daysAsleepAtMinute := getSleepiestMinute(naps, guardID)
-		if daysAsleepAtMinute > resultDaysAsleepAtMinute {
-			resultGuardID = guardID
-			resultMinute = sleepiestMinute
-			resultDaysAsleepAtMinute = daysAsleepAtMinute
-		}
+		return "", nil, err
 	}
-	return strconv.Itoa(resultGuardID * resultMinute), nil
+	return strconv.Itoa(ranking.Answer()), ranking.Caveats(), nil
 }
 
+// parseInput returns every completed nap in the log.
 func parseInput(input string) ([]guardNap, error) {
 	naps := []guardNap{}
-	events, err := parseEvents(input)
+	shifts, err := parseShifts(input)
 	if err != nil {
 		return naps, err
 	}
-	napInProgress := false
+	for _, shift := range shifts {
+		naps = append(naps, shift.Naps...)
+	}
+	return naps, nil
+}
+
+// parseShifts replays the log in chronological order, grouping the naps by
+// the shift during which they happened.
+func parseShifts(input string) ([]guardShift, error) {
+	shifts := []guardShift{}
+	events, err := parseEvents(input)
+	if err != nil {
+		return shifts, err
+	}
+	asleep := false
 	for _, event := range events {
-		if !napInProgress && !event.Awake {
-			napInProgress = true
-			nap := guardNap{
-				GuardID:   event.GuardID,
+		current := len(shifts) - 1
+		switch event.Kind {
+		case beginShift:
+			if asleep {
+				return shifts, &LogError{Kind: ShiftOverlap, Line: event.Line}
+			}
+			shifts = append(shifts, newGuardShift(event.GuardID, event.EventTime))
+		case fallAsleep:
+			if current < 0 {
+				return shifts, &LogError{Kind: NoGuardOnDuty, Line: event.Line}
+			}
+			if asleep {
+				return shifts, &LogError{Kind: SleepWhileAsleep, Line: event.Line}
+			}
+			asleep = true
+			shifts[current].Naps = append(shifts[current].Naps, guardNap{
+				GuardID:   shifts[current].GuardID,
 				StartTime: event.EventTime,
-				EndTime:   event.EventTime.Add(time.Duration(-1)), // placeholder
+				EndTime:   event.EventTime, // placeholder
+			})
+		case wakeUp:
+			if !asleep {
+				return shifts, &LogError{Kind: WakeWithoutSleep, Line: event.Line}
 			}
-			naps = append(naps, nap)
-		}
-		if napInProgress && event.Awake {
-			// TODO: make sure nap doesn't span multiple days
-			napInProgress = false
+			asleep = false
+			naps := shifts[current].Naps
 			naps[len(naps)-1].EndTime = event.EventTime
 		}
 	}
-	return naps, nil
+	if asleep {
+		return shifts, &LogError{Kind: UnfinishedNap, Line: events[len(events)-1].Line}
+	}
+	return shifts, nil
 }
 
+// parseEvents parses every line of the log and sorts the events by time. The
+// log doesn't need to be in order, but events with the same timestamp keep
+// their relative order.
 func parseEvents(input string) ([]guardEvent, error) {
 	lines := strings.Split(strings.TrimSpace(input), "\n")
-	sort.Strings(lines)
 	events := make([]guardEvent, len(lines))
 	for i, line := range lines {
-		event, err := parseLine(line)
+		event, err := parseLine(strings.TrimSpace(line))
 		if err != nil {
 			return events, err
 		}
-		if event.GuardID == unknownGuardID {
-			if i == 0 {
-				return events, fmt.Errorf("line has unknown guard: %s", line)
-			}
-			event.GuardID = events[i-1].GuardID
-		}
 		events[i] = event
 	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].EventTime.Before(events[j].EventTime)
+	})
 	return events, nil
 }
 
 func parseLine(line string) (event guardEvent, err error) {
-	components := strings.Split(line[1:], "] ")
+	event.Line = line
+	if !strings.HasPrefix(line, "[") {
+		return event, &LogError{Kind: MalformedEntry, Line: line}
+	}
+	components := strings.SplitN(line[1:], "] ", 2)
 	if len(components) != 2 {
-		err = fmt.Errorf("invalid line: %s", line)
-		return
+		return event, &LogError{Kind: MalformedEntry, Line: line}
 	}
 	rawTime, description := components[0], components[1]
 	timeFormat := "2006-01-02 15:04"
-	eventTime, err := time.Parse(timeFormat, rawTime)
-	if err != nil {
-		return
-	}
-	event.EventTime = eventTime
+	event.EventTime, err = time.Parse(timeFormat, rawTime)
 	if err != nil {
-		return
+		return event, &LogError{Kind: MalformedEntry, Line: line, Err: err}
 	}
 	switch description {
 	case "wakes up":
-		event.Awake = true
-		event.GuardID = unknownGuardID
+		event.Kind = wakeUp
 	case "falls asleep":
-		event.Awake = false
-		event.GuardID = unknownGuardID
+		event.Kind = fallAsleep
 	default:
+		if !strings.HasPrefix(description, "Guard #") || !strings.HasSuffix(description, " begins shift") {
+			return event, &LogError{Kind: MalformedEntry, Line: line}
+		}
 		rawGuardID := strings.TrimSuffix(strings.TrimPrefix(description, "Guard #"), " begins shift")
-		guardID, atoiErr := strconv.Atoi(rawGuardID)
-		if atoiErr != nil {
-			return event, atoiErr
+		event.GuardID, err = strconv.Atoi(rawGuardID)
+		if err != nil {
+			return event, &LogError{Kind: MalformedEntry, Line: line, Err: err}
 		}
-		event.GuardID = guardID
-		event.Awake = true
+		event.Kind = beginShift
 	}
-	return
+	return event, nil
 }
 
-func getSleepiestGuard(naps []guardNap) int {
-	guardMinutesAsleep := make(map[int]int)
+// sleepiestMinutes returns the minutes, in order, during which the given
+// guard was most often asleep, along with the number of days on which the
+// guard was asleep at those minutes. There are no such minutes if the guard
+// never slept.
+func sleepiestMinutes(naps []guardNap, guardID int) ([]int, int) {
+	napMinutes := []interval.Interval{}
 	for _, nap := range naps {
-		napDuration := nap.EndTime.Sub(nap.StartTime)
-		guardMinutesAsleep[nap.GuardID] += int(napDuration.Minutes())
-	}
-	sleepiestGuardID := -1
-	sleepiestGuardMinutesAsleep := 0
-	for guardID, minutesAsleep := range guardMinutesAsleep {
-		if minutesAsleep > sleepiestGuardMinutesAsleep {
-			sleepiestGuardID = guardID
-			sleepiestGuardMinutesAsleep = minutesAsleep
+		if nap.GuardID != guardID {
+			continue
 		}
+		napMinutes = append(napMinutes, nap.midnightMinutes()...)
 	}
-	return sleepiestGuardID
-}
+	// The intervals are half-open, so a guard is counted as awake in the
+	// minute they wake up.
+	asleepMoments := interval.Histogram(napMinutes, midnightHour.Start, midnightHour.End)
 
-// Returns the minute during which the given guard was most often asleep, along
-// with the number of days in which the guard was asleep at that minute.
-func getSleepiestMinute(naps []guardNap, guardID int) (int, int) {
-	asleepMoments := make(map[int]int, 60)
-	for _, nap := range naps {
-		if nap.GuardID != guardID {
+	minutes := []int{}
+	maxDays := 0
+	for minute, daysAsleepAtMinute := range asleepMoments {
+		if daysAsleepAtMinute == 0 || daysAsleepAtMinute < maxDays {
 			continue
 		}
-		// Assumes naps always start and end between 00:00 and 00:59.
-		startMinute, endMinute := nap.StartTime.Minute(), nap.EndTime.Minute()
-		// Must be up to and *not including* endMinute, because a guard is
-		// counted as awake in the minute they wake up.
-		for min := startMinute; min < endMinute; min++ {
-			asleepMoments[min]++
+		if daysAsleepAtMinute > maxDays {
+			minutes = minutes[:0]
+			maxDays = daysAsleepAtMinute
 		}
+		minutes = append(minutes, minute)
 	}
+	return minutes, maxDays
+}
 
-	sleepiestMinute := 0
-	for minute, daysAsleepAtMinute := range asleepMoments {
-		if daysAsleepAtMinute > asleepMoments[sleepiestMinute] {
-			sleepiestMinute = minute
-		}
+// Returns the earliest minute during which the given guard was most often
+// asleep, along with the number of days in which the guard was asleep at that
+// minute. The minute is -1 if the guard never slept.
+func getSleepiestMinute(naps []guardNap, guardID int) (int, int) {
+	minutes, days := sleepiestMinutes(naps, guardID)
+	if len(minutes) == 0 {
+		return -1, 0
 	}
-	return sleepiestMinute, asleepMoments[sleepiestMinute]
+	return minutes[0], days
 }
@@ -0,0 +1,212 @@
+package day04
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orn688/advent-of-code-2018/internal/interval"
+	"github.com/orn688/advent-of-code-2018/internal/testutil"
+)
+
+const input = `
+[1518-11-01 00:00] Guard #10 begins shift
+[1518-11-01 00:05] falls asleep
+[1518-11-01 00:25] wakes up
+[1518-11-01 00:30] falls asleep
+[1518-11-01 00:55] wakes up
+[1518-11-01 23:58] Guard #99 begins shift
+[1518-11-02 00:40] falls asleep
+[1518-11-02 00:50] wakes up
+[1518-11-03 00:05] Guard #10 begins shift
+[1518-11-03 00:24] falls asleep
+[1518-11-03 00:29] wakes up
+[1518-11-04 00:02] Guard #99 begins shift
+[1518-11-04 00:36] falls asleep
+[1518-11-04 00:46] wakes up
+[1518-11-05 00:03] Guard #99 begins shift
+[1518-11-05 00:45] falls asleep
+[1518-11-05 00:55] wakes up
+`
+
+func TestPart1(t *testing.T) {
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Name: "example", Input: input, Expected: "240"},
+	})
+}
+
+func TestPart2(t *testing.T) {
+	testutil.RunExamples(t, Part2, []testutil.Example{
+		{Name: "example", Input: input, Expected: "4455"},
+	})
+}
+
+func TestUnsortedLog(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(input), "\n")
+	for i, j := range rand.New(rand.NewSource(1)).Perm(len(lines)) {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Name: "shuffled", Input: strings.Join(lines, "\n"), Expected: "240"},
+	})
+}
+
+func TestNapsOutsideMidnightHour(t *testing.T) {
+	log := `
+[1518-11-01 23:58] Guard #10 begins shift
+[1518-11-01 23:59] falls asleep
+[1518-11-02 00:03] wakes up
+[1518-11-02 00:50] falls asleep
+[1518-11-02 01:10] wakes up
+[1518-11-02 23:50] Guard #7 begins shift
+[1518-11-03 00:02] falls asleep
+[1518-11-03 00:04] wakes up
+`
+	naps, err := parseInput(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Guard #10 naps from 00:00 to 00:03 and 00:50 to 00:59 within the
+	// midnight hour, for 13 minutes in all.
+	ranking, err := RankStrategy1(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if top := ranking[0]; top.GuardID != 10 || top.Score != 13 {
+		t.Errorf("expected guard 10 with 13 minutes, actual %+v", top)
+	}
+	if minute, days := getSleepiestMinute(naps, 7); minute != 2 || days != 1 {
+		t.Errorf("expected minute 2 on 1 day, actual minute %d on %d days", minute, days)
+	}
+	counts := interval.Histogram(naps[1].midnightMinutes(), 0, 60)
+	if counts[49] != 0 || counts[50] != 1 || counts[59] != 1 {
+		t.Errorf("expected the nap to cover 00:50 to 00:59, actual %v", counts)
+	}
+}
+
+func TestInvalidLogs(t *testing.T) {
+	testcases := []struct {
+		log      string
+		expected LogErrorKind
+	}{
+		{"[1518-11-01 00:00] Guard #10 begins shift\n[1518-11-01 00:05] wakes up", WakeWithoutSleep},
+		{"[1518-11-01 00:00] Guard #10 begins shift\n[1518-11-01 00:05] falls asleep\n[1518-11-01 00:06] falls asleep", SleepWhileAsleep},
+		{"[1518-11-01 00:05] falls asleep\n[1518-11-01 00:25] wakes up", NoGuardOnDuty},
+		{"[1518-11-01 00:00] Guard #10 begins shift\n[1518-11-01 00:05] falls asleep\n[1518-11-01 00:10] Guard #11 begins shift", ShiftOverlap},
+		{"[1518-11-01 00:00] Guard #10 begins shift\n[1518-11-01 00:05] falls asleep", UnfinishedNap},
+		{"[1518-11-01 00:00] Guard #ten begins shift", MalformedEntry},
+		{"[1518-13-01 00:00] Guard #10 begins shift", MalformedEntry},
+		{"[1518-11-01 00:00] Guard #10 starts singing", MalformedEntry},
+		{"1518-11-01 00:00 wakes up", MalformedEntry},
+		{"", MalformedEntry},
+	}
+	for _, tc := range testcases {
+		_, err := parseInput(tc.log)
+		logErr, ok := err.(*LogError)
+		if !ok || logErr.Kind != tc.expected {
+			t.Errorf("%q: expected error kind %d, actual %v", tc.log, tc.expected, err)
+		}
+	}
+}
+
+func TestSleepReportTable(t *testing.T) {
+	// From the puzzle statement.
+	expected := `
+Date   ID   Minute
+            000000000011111111112222222222333333333344444444445555555555
+            012345678901234567890123456789012345678901234567890123456789
+11-01  #10  .....####################.....#########################.....
+11-02  #99  ........................................##########..........
+11-03  #10  ........................#####...............................
+11-04  #99  ....................................##########..............
+11-05  #99  .............................................##########.....
+`
+	report, err := NewSleepReport(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := report.WriteTable(&sb); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertGridEqual(t, expected, sb.String())
+	testutil.AssertWriteErrors(t, report.WriteTable)
+	testutil.AssertWriteErrors(t, report.WriteTableCSV)
+}
+
+func TestSleepReportSummary(t *testing.T) {
+	report, err := NewSleepReport(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := report.WriteSummary(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := report.WriteSummaryCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertGolden(t, "summary", buf.Bytes())
+	testutil.AssertWriteErrors(t, report.WriteSummary)
+	testutil.AssertWriteErrors(t, report.WriteSummaryCSV)
+}
+
+func TestRankingTies(t *testing.T) {
+	// Guards #5 and #3 both sleep for 10 minutes, and #3 has two sleepiest
+	// minutes.
+	log := `
+[1518-11-01 00:00] Guard #5 begins shift
+[1518-11-01 00:10] falls asleep
+[1518-11-01 00:20] wakes up
+[1518-11-02 00:00] Guard #3 begins shift
+[1518-11-02 00:30] falls asleep
+[1518-11-02 00:35] wakes up
+[1518-11-03 00:00] Guard #3 begins shift
+[1518-11-03 00:33] falls asleep
+[1518-11-03 00:38] wakes up
+[1518-11-04 00:00] Guard #7 begins shift
+[1518-11-04 00:40] falls asleep
+[1518-11-04 00:41] wakes up
+`
+	for i := 0; i < 10; i++ {
+		ranking, err := RankStrategy1(log)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := Ranking{
+			{GuardID: 3, Score: 10, Minute: 33, TiedMinutes: []int{34}},
+			{GuardID: 5, Score: 10, Minute: 10, TiedMinutes: []int{11, 12, 13, 14, 15, 16, 17, 18, 19}},
+			{GuardID: 7, Score: 1, Minute: 40, TiedMinutes: []int{}},
+		}
+		if !reflect.DeepEqual(expected, ranking) {
+			t.Fatalf("expected %+v, actual %+v", expected, ranking)
+		}
+		if !ranking.TopTied() || ranking.Answer() != 99 {
+			t.Errorf("expected a tied answer of 99, actual %d", ranking.Answer())
+		}
+	}
+
+	ranking, err := RankStrategy2(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranking[0].GuardID != 3 || ranking[0].Score != 2 || !ranking.TopTied() {
+		t.Errorf("expected guard 3 to lead with 2 days, tied, actual %+v", ranking)
+	}
+	if caveats := ranking.Caveats(); !reflect.DeepEqual(caveats, []string{TiedCaveat}) {
+		t.Errorf("expected %v, actual %v", []string{TiedCaveat}, caveats)
+	}
+	if answer, caveats, err := Part2WithCaveats(log); err != nil || answer != "99" || len(caveats) != 1 {
+		t.Errorf("expected a tied answer of 99, actual %s with caveats %v (%v)", answer, caveats, err)
+	}
+
+	ranking, err = RankStrategy2(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranking.TopTied() || ranking.Caveats() != nil {
+		t.Errorf("expected no tie in the example, actual %+v", ranking)
+	}
+}
@@ -0,0 +1,109 @@
+package day04
+
+import (
+	"errors"
+	"sort"
+)
+
+// A Ranking orders the guards by how well they fit one of the two strategies,
+// best first. Guards are ranked by Score, highest first, and guards with equal
+// scores are ordered by ID, lowest first.
+type Ranking []RankedGuard
+
+// A RankedGuard is a guard's standing under a strategy.
+type RankedGuard struct {
+	GuardID int
+	// Score is what the strategy ranks guards by: the total minutes asleep
+	// for strategy 1, or the number of days asleep at Minute for strategy 2.
+	Score int
+	// Minute is the guard's sleepiest minute. If several minutes tie, it is
+	// the earliest and the others are listed in TiedMinutes.
+	Minute      int
+	TiedMinutes []int
+}
+
+// Answer returns the puzzle's answer for the top-ranked guard: their ID
+// multiplied by their sleepiest minute.
+func (r Ranking) Answer() int {
+	return r[0].GuardID * r[0].Minute
+}
+
+// TopTied reports whether the answer depends on tie-breaking, either because
+// another guard scores as well as the top one or because the top guard has
+// more than one sleepiest minute.
+func (r Ranking) TopTied() bool {
+	return (len(r) > 1 && r[1].Score == r[0].Score) || len(r[0].TiedMinutes) > 0
+}
+
+// TiedCaveat is the caveat to show alongside an answer that depends on
+// tie-breaking.
+const TiedCaveat = "the top answer is tied and was chosen by tie-breaking"
+
+// Caveats returns any caveats about the ranking's answer.
+func (r Ranking) Caveats() []string {
+	if r.TopTied() {
+		return []string{TiedCaveat}
+	}
+	return nil
+}
+
+// RankStrategy1 ranks the guards by the total number of minutes they spent
+// asleep.
+func RankStrategy1(input string) (Ranking, error) {
+	return rankGuards(input, func(guard RankedGuard, naps []guardNap) int {
+		minutesAsleep := 0
+		for _, nap := range naps {
+			if nap.GuardID != guard.GuardID {
+				continue
+			}
+			for _, minutes := range nap.midnightMinutes() {
+				minutesAsleep += minutes.Len()
+			}
+		}
+		return minutesAsleep
+	})
+}
+
+// RankStrategy2 ranks the guards by the number of days on which they were
+// asleep at their sleepiest minute.
+func RankStrategy2(input string) (Ranking, error) {
+	return rankGuards(input, func(guard RankedGuard, naps []guardNap) int {
+		_, days := sleepiestMinutes(naps, guard.GuardID)
+		return days
+	})
+}
+
+// rankGuards ranks every guard who fell asleep at least once by the given
+// scoring function.
+func rankGuards(input string, score func(RankedGuard, []guardNap) int) (Ranking, error) {
+	naps, err := parseInput(input)
+	if err != nil {
+		return nil, err
+	}
+	ranking := Ranking{}
+	seen := make(map[int]bool)
+	for _, nap := range naps {
+		if seen[nap.GuardID] {
+			continue
+		}
+		seen[nap.GuardID] = true
+		minutes, _ := sleepiestMinutes(naps, nap.GuardID)
+		if len(minutes) == 0 {
+			// Only asleep outside of the midnight hour.
+			continue
+		}
+		guard := RankedGuard{GuardID: nap.GuardID, Minute: minutes[0], TiedMinutes: minutes[1:]}
+		guard.Score = score(guard, naps)
+		ranking = append(ranking, guard)
+	}
+	if len(ranking) == 0 {
+		return nil, errors.New("no guard ever falls asleep")
+	}
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].Score != ranking[j].Score {
+			return ranking[i].Score > ranking[j].Score
+		}
+		return ranking[i].GuardID < ranking[j].GuardID
+	})
+	return ranking, nil
+}
@@ -0,0 +1,226 @@
+package day04
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/orn688/advent-of-code-2018/internal/interval"
+)
+
+// A SleepReport lays out when each guard was asleep, both night by night and
+// summed up per guard.
+type SleepReport struct {
+	Nights []NightRecord
+	// Guards is ordered by guard ID.
+	Guards []GuardSummary
+}
+
+// A NightRecord is one row of the puzzle's table: the minutes of a night's
+// midnight hour during which the guard on duty was asleep.
+type NightRecord struct {
+	Date    string // MM-DD
+	GuardID int
+	Asleep  [60]bool
+}
+
+// A GuardSummary totals up a single guard's naps.
+type GuardSummary struct {
+	GuardID       int
+	MinutesAsleep int
+	NapCount      int
+	// SleepiestMinute is the minute that the guard was asleep on the most
+	// days, which was DaysAsleepAtMinute days. It is -1 if the guard never
+	// slept during the midnight hour.
+	SleepiestMinute    int
+	DaysAsleepAtMinute int
+	// Histogram holds the number of days the guard was asleep at each minute
+	// of the midnight hour.
+	Histogram []int
+}
+
+// NewSleepReport builds the report for the log in the input.
+func NewSleepReport(input string) (SleepReport, error) {
+	shifts, err := parseShifts(input)
+	if err != nil {
+		return SleepReport{}, err
+	}
+
+	report := SleepReport{Nights: make([]NightRecord, len(shifts))}
+	napsByGuard := make(map[int][]guardNap)
+	for i, shift := range shifts {
+		night := NightRecord{Date: shift.Date.Format("01-02"), GuardID: shift.GuardID}
+		for _, nap := range shift.Naps {
+			for _, minutes := range nap.midnightMinutes() {
+				for minute := minutes.Start; minute < minutes.End; minute++ {
+					night.Asleep[minute] = true
+				}
+			}
+		}
+		report.Nights[i] = night
+		// Guards who never nap still get a summary.
+		napsByGuard[shift.GuardID] = append(napsByGuard[shift.GuardID], shift.Naps...)
+	}
+
+	for guardID, naps := range napsByGuard {
+		minutes := []interval.Interval{}
+		for _, nap := range naps {
+			minutes = append(minutes, nap.midnightMinutes()...)
+		}
+		summary := GuardSummary{
+			GuardID:   guardID,
+			NapCount:  len(naps),
+			Histogram: interval.Histogram(minutes, midnightHour.Start, midnightHour.End),
+		}
+		for _, iv := range minutes {
+			summary.MinutesAsleep += iv.Len()
+		}
+		summary.SleepiestMinute, summary.DaysAsleepAtMinute = getSleepiestMinute(naps, guardID)
+		report.Guards = append(report.Guards, summary)
+	}
+	sort.Slice(report.Guards, func(i, j int) bool {
+		return report.Guards[i].GuardID < report.Guards[j].GuardID
+	})
+	return report, nil
+}
+
+// WriteTable writes the nights in the same format as the puzzle, with a '#'
+// for each minute that the guard was asleep and a '.' otherwise.
+func (r SleepReport) WriteTable(w io.Writer) error {
+	idWidth := len("ID")
+	for _, night := range r.Nights {
+		if width := len(strconv.Itoa(night.GuardID)) + 1; width > idWidth {
+			idWidth = width
+		}
+	}
+	var tens, ones strings.Builder
+	for minute := 0; minute < 60; minute++ {
+		tens.WriteByte(byte('0' + minute/10))
+		ones.WriteByte(byte('0' + minute%10))
+	}
+	if _, err := fmt.Fprintf(w, "Date   %-*s  Minute\n", idWidth, "ID"); err != nil {
+		return err
+	}
+	for _, digits := range []string{tens.String(), ones.String()} {
+		if _, err := fmt.Fprintf(w, "       %*s  %s\n", idWidth, "", digits); err != nil {
+			return err
+		}
+	}
+	for _, night := range r.Nights {
+		if _, err := fmt.Fprintf(w, "%s  %-*s  %s\n", night.Date, idWidth, "#"+strconv.Itoa(night.GuardID), night.minutes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (night NightRecord) minutes() string {
+	var sb strings.Builder
+	for _, asleep := range night.Asleep {
+		if asleep {
+			sb.WriteByte('#')
+		} else {
+			sb.WriteByte('.')
+		}
+	}
+	return sb.String()
+}
+
+// WriteTableCSV writes the nights as CSV, with a 1 for each minute that the
+// guard was asleep and a 0 otherwise.
+func (r SleepReport) WriteTableCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{"date", "guard"}, minuteHeaders()...)); err != nil {
+		return err
+	}
+	for _, night := range r.Nights {
+		row := []string{night.Date, strconv.Itoa(night.GuardID)}
+		for _, asleep := range night.Asleep {
+			if asleep {
+				row = append(row, "1")
+			} else {
+				row = append(row, "0")
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteSummary writes a line of totals per guard, ending with the guard's
+// histogram: the number of days asleep at each minute from 00:00 to 00:59,
+// with '.' for none and '+' for more than 9.
+func (r SleepReport) WriteSummary(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%-8s %8s %5s %10s %5s  %s\n",
+		"Guard", "Minutes", "Naps", "Sleepiest", "Days", "Histogram"); err != nil {
+		return err
+	}
+	for _, guard := range r.Guards {
+		sleepiest := "-"
+		if guard.SleepiestMinute >= 0 {
+			sleepiest = fmt.Sprintf("00:%02d", guard.SleepiestMinute)
+		}
+		if _, err := fmt.Fprintf(w, "%-8s %8d %5d %10s %5d  %s\n",
+			"#"+strconv.Itoa(guard.GuardID), guard.MinutesAsleep, guard.NapCount,
+			sleepiest, guard.DaysAsleepAtMinute, histogramString(guard.Histogram)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func histogramString(histogram []int) string {
+	var sb strings.Builder
+	for _, days := range histogram {
+		switch {
+		case days == 0:
+			sb.WriteByte('.')
+		case days > 9:
+			sb.WriteByte('+')
+		default:
+			sb.WriteByte(byte('0' + days))
+		}
+	}
+	return sb.String()
+}
+
+// WriteSummaryCSV writes the per-guard totals along with each guard's
+// histogram of days asleep at each minute.
+func (r SleepReport) WriteSummaryCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := []string{"guard", "minutes_asleep", "naps", "sleepiest_minute", "days_asleep_at_minute"}
+	if err := writer.Write(append(header, minuteHeaders()...)); err != nil {
+		return err
+	}
+	for _, guard := range r.Guards {
+		row := []string{
+			strconv.Itoa(guard.GuardID),
+			strconv.Itoa(guard.MinutesAsleep),
+			strconv.Itoa(guard.NapCount),
+			strconv.Itoa(guard.SleepiestMinute),
+			strconv.Itoa(guard.DaysAsleepAtMinute),
+		}
+		for _, days := range guard.Histogram {
+			row = append(row, strconv.Itoa(days))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func minuteHeaders() []string {
+	headers := make([]string, 60)
+	for minute := range headers {
+		headers[minute] = fmt.Sprintf("00:%02d", minute)
+	}
+	return headers
+}
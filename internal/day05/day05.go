@@ -1,10 +1,11 @@
 package day05
 
 import (
-	"container/list"
 	"fmt"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 )
 
@@ -15,95 +16,82 @@ func Part1(input string) (string, error) {
 		return "", err
 	}
 
-	collapse(units)
-	return strconv.Itoa(units.Len()), nil
+	return strconv.Itoa(len(collapse(units, CaseRules{}))), nil
 }
 
 // Part2 returns the shortest possible polymer length after removing all
-// instances of any one chosen character (case-insensitive) and then collapsing
-// the polymer.
+// units of any one type (of either polarity) and then collapsing the polymer.
+//
+// Removing a unit type after collapsing gives the same result as removing it
+// first, since every reaction between other units still takes place, so each
+// removal starts from the (much shorter) collapsed polymer. Only the types that
+// survive the first collapse are worth removing, and the removals are
+// independent so they're shared out between a pool of workers.
 func Part2(input string) (string, error) {
-	baseUnits, err := parseInput(input)
+	units, err := parseInput(input)
 	if err != nil {
 		return "", err
 	}
-	minLength := baseUnits.Len()
-	for char := 'a'; char <= 'z'; char++ {
-		units := copy(baseUnits)
-		removeChar(units, char)
-		collapse(units)
-		if units.Len() < minLength {
-			minLength = units.Len()
-		}
-	}
-	return strconv.Itoa(minLength), nil
-}
+	collapsed := collapse(units, CaseRules{})
 
-func parseInput(input string) (*list.List, error) {
-	units := list.New()
-	for _, char := range strings.TrimSpace(input) {
-		if !('a' <= char && char <= 'z') && !('A' <= char && char <= 'Z') {
-			return units, fmt.Errorf("invalid character: '%c'", char)
+	types := []rune{}
+	seen := map[rune]bool{}
+	for _, unit := range collapsed {
+		if key := foldKey(unit.Type); !seen[key] {
+			seen[key] = true
+			types = append(types, key)
 		}
-		units.PushBack(char)
 	}
-	return units, nil
-}
 
-// Collapses the polymer in-place.
-func collapse(units *list.List) {
-	current := units.Front()
-	for current != nil {
-		next := current.Next()
-		if next == nil {
-			break
-		}
-		if canCollapse(current, next) {
-			newCurrent := current.Prev()
-			if newCurrent == nil {
-				newCurrent = next.Next()
+	unitTypes := make(chan rune)
+	lengths := make(chan int)
+	var workers sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for unitType := range unitTypes {
+				lengths <- len(collapse(removeType(collapsed, unitType), CaseRules{}))
 			}
-			units.Remove(next)
-			units.Remove(current)
-			current = newCurrent
-		} else {
-			current = next
-		}
+		}()
 	}
-}
-
-func canCollapse(left *list.Element, right *list.Element) bool {
-	leftChar, rightChar := left.Value.(int32), right.Value.(int32)
-	// The lower and upper versions of character are 32 codepoints apart.
-	// For example, A = 65 and a = 97.
-	return abs(leftChar-rightChar) == 32
-}
+	go func() {
+		for _, unitType := range types {
+			unitTypes <- unitType
+		}
+		close(unitTypes)
+		workers.Wait()
+		close(lengths)
+	}()
 
-func abs(x int32) int32 {
-	if x < 0 {
-		return -1 * x
+	minLength := len(collapsed)
+	for length := range lengths {
+		if length < minLength {
+			minLength = length
+		}
 	}
-	return x
+	return strconv.Itoa(minLength), nil
 }
 
-func copy(lst *list.List) *list.List {
-	listCopy := list.New()
-	for element := lst.Front(); element != nil; element = element.Next() {
-		listCopy.PushBack(element.Value)
+// parseInput returns the units of the polymer, which must all be letters.
+func parseInput(input string) ([]Unit, error) {
+	units := units(strings.TrimSpace(input))
+	for _, unit := range units {
+		if !unicode.IsLetter(unit.Type) {
+			return units, fmt.Errorf("invalid character: '%c'", unit.Type)
+		}
 	}
-	return listCopy
+	return units, nil
 }
 
-// Remove all instances of char in place (case-insensitive).
-func removeChar(units *list.List, char rune) {
-	element := units.Front()
-	upper, lower := unicode.ToUpper(char), unicode.ToLower(char)
-	for element != nil {
-		next := element.Next()
-		value := element.Value.(rune)
-		if value == upper || value == lower {
-			units.Remove(element)
+// Returns a copy of units with all units of the type identified by the given
+// fold key removed.
+func removeType(units []Unit, key rune) []Unit {
+	removed := make([]Unit, 0, len(units))
+	for _, unit := range units {
+		if foldKey(unit.Type) != key {
+			removed = append(removed, unit)
 		}
-		element = next
 	}
+	return removed
 }
@@ -0,0 +1,278 @@
+package day05
+
+import (
+	"container/list"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/orn688/advent-of-code-2018/internal/testutil"
+)
+
+func TestPart1(t *testing.T) {
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Input: "aA", Expected: "0"},
+		{Input: "abBA", Expected: "0"},
+		{Input: "abAB", Expected: "4"},
+		{Input: "aabAAB", Expected: "6"},
+		{Input: "dabAcCaCBAcCcaDA", Expected: "10"},
+	})
+}
+
+func TestPart2(t *testing.T) {
+	testutil.RunExamples(t, Part2, []testutil.Example{
+		{Input: "dabAcCaCBAcCcaDA", Expected: "4"},
+	})
+}
+
+func TestPart1Unicode(t *testing.T) {
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Name: "Greek", Input: "αβΒΑγ", Expected: "1"},
+		{Name: "sharp s", Input: "ßẞ", Expected: "0"},
+		{Name: "final sigma", Input: "ΣσςΣ", Expected: "0"},
+		{Name: "same polarity", Input: "σς", Expected: "2"},
+		{Name: "Kelvin sign", Input: "\u212Ak\u212AK", Expected: "2"},
+	})
+}
+
+func TestPart2Unicode(t *testing.T) {
+	testutil.RunExamples(t, Part2, []testutil.Example{
+		{Input: "ΔαβΑΒδ", Expected: "0"},
+	})
+}
+
+func TestParseInput(t *testing.T) {
+	for _, input := range []string{"ab1A", "ab A", "ab-"} {
+		if _, err := parseInput(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestReduce(t *testing.T) {
+	actual := Reduce("dabAcCaCBAcCcaDA", CaseRules{})
+	expected := []Unit{
+		{'d', 0}, {'a', 1}, {'b', 2}, {'C', 7}, {'B', 8},
+		{'A', 9}, {'c', 12}, {'a', 13}, {'D', 14}, {'A', 15},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+	if polymer := String(actual); polymer != "dabCBAcaDA" {
+		t.Errorf("expected %v, actual %v", "dabCBAcaDA", polymer)
+	}
+	// These are 32 code points apart but aren't letters.
+	if actual := Reduce("@`[{", CaseRules{}); len(actual) != 4 {
+		t.Errorf("expected %v, actual %v", 4, len(actual))
+	}
+}
+
+func TestPairRules(t *testing.T) {
+	rules, err := ParsePairRules("a b\n\nc c\nx Ж\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Unit{{'a', 0}, {'A', 1}, {'z', 8}}
+	if actual := Reduce("aAbaccЖxz", rules); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+
+	for _, input := range []string{"a", "a b c", "ab c"} {
+		if _, err := ParsePairRules(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+// randomPolymer returns a polymer that reacts a lot, by only using a few unit
+// types.
+func randomPolymer(random *rand.Rand, length int) string {
+	units := []rune("aAbBcCdD")
+	polymer := make([]rune, length)
+	for i := range polymer {
+		polymer[i] = units[random.Intn(len(units))]
+	}
+	return string(polymer)
+}
+
+func TestMatchesList(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		polymer := randomPolymer(random, random.Intn(200))
+		expected, _ := part1List(polymer)
+		if actual, _ := Part1(polymer); actual != expected {
+			t.Errorf("%s: expected %s, actual %s", polymer, expected, actual)
+		}
+		expected, _ = part2List(polymer)
+		if actual, _ := Part2(polymer); actual != expected {
+			t.Errorf("%s: expected %s, actual %s", polymer, expected, actual)
+		}
+	}
+}
+
+var benchmarkPolymer = randomPolymer(rand.New(rand.NewSource(1)), 50000)
+
+func BenchmarkPart1(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Part1(benchmarkPolymer)
+	}
+}
+
+func BenchmarkPart1List(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		part1List(benchmarkPolymer)
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Part2(benchmarkPolymer)
+	}
+}
+
+func BenchmarkPart2List(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		part2List(benchmarkPolymer)
+	}
+}
+
+// The original container/list implementation, kept as a reference.
+
+func part1List(input string) (string, error) {
+	units := parseInputList(input)
+	collapseList(units)
+	return strconv.Itoa(units.Len()), nil
+}
+
+func part2List(input string) (string, error) {
+	baseUnits := parseInputList(input)
+	minLength := baseUnits.Len()
+	for char := 'a'; char <= 'z'; char++ {
+		units := copyList(baseUnits)
+		removeCharList(units, char)
+		collapseList(units)
+		if units.Len() < minLength {
+			minLength = units.Len()
+		}
+	}
+	return strconv.Itoa(minLength), nil
+}
+
+func parseInputList(input string) *list.List {
+	units := list.New()
+	for _, char := range input {
+		units.PushBack(char)
+	}
+	return units
+}
+
+func collapseList(units *list.List) {
+	current := units.Front()
+	for current != nil {
+		next := current.Next()
+		if next == nil {
+			break
+		}
+		if abs(current.Value.(rune)-next.Value.(rune)) == 32 {
+			newCurrent := current.Prev()
+			if newCurrent == nil {
+				newCurrent = next.Next()
+			}
+			units.Remove(next)
+			units.Remove(current)
+			current = newCurrent
+		} else {
+			current = next
+		}
+	}
+}
+
+func copyList(lst *list.List) *list.List {
+	listCopy := list.New()
+	for element := lst.Front(); element != nil; element = element.Next() {
+		listCopy.PushBack(element.Value)
+	}
+	return listCopy
+}
+
+func removeCharList(units *list.List, char rune) {
+	element := units.Front()
+	for element != nil {
+		next := element.Next()
+		value := element.Value.(rune)
+		if value == char || value == char-32 {
+			units.Remove(element)
+		}
+		element = next
+	}
+}
+
+func abs(x int32) int32 {
+	if x < 0 {
+		return -1 * x
+	}
+	return x
+}
+
+func TestReduceReader(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		polymer := randomPolymer(random, random.Intn(200))
+		var reports []Progress
+		reducer, err := ReduceReader(strings.NewReader(polymer+"\n"), CaseRules{}, true, func(progress Progress) {
+			reports = append(reports, progress)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := Reduce(polymer, CaseRules{})
+		if actual := reducer.Units(); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected %v, actual %v", polymer, expected, actual)
+		}
+		expectedReports := []Progress{{Bytes: int64(len(polymer) + 1), Units: len(polymer), Surviving: len(expected)}}
+		if !reflect.DeepEqual(reports, expectedReports) {
+			t.Errorf("expected %v, actual %v", expectedReports, reports)
+		}
+	}
+
+	for _, input := range []string{"ab\xffc", "ab1A", "ab A", "ab-"} {
+		if _, err := ReduceReader(strings.NewReader(input), CaseRules{}, false, nil); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestReducerWithoutPositions(t *testing.T) {
+	reducer, err := ReduceReader(strings.NewReader("  dabAcCaCBAcCcaDA\n"), CaseRules{}, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reducer.Len() != 10 || reducer.String() != "dabCBAcaDA" {
+		t.Errorf("expected %d units %s, actual %d units %s", 10, "dabCBAcaDA", reducer.Len(), reducer)
+	}
+	if units := reducer.Units(); units[0] != (Unit{'d', -1}) {
+		t.Errorf("expected %v, actual %v", Unit{'d', -1}, units[0])
+	}
+
+	// Units of any width are popped whole.
+	reducer, _ = ReduceReader(strings.NewReader("aßΣσẞ"), CaseRules{}, false, nil)
+	if reducer.String() != "a" {
+		t.Errorf("expected %s, actual %s", "a", reducer)
+	}
+}
+
+func TestGeneratePolymer(t *testing.T) {
+	var polymer strings.Builder
+	if err := GeneratePolymer(&polymer, 1000, 3, 1); err != nil {
+		t.Fatal(err)
+	}
+	units := strings.TrimSuffix(polymer.String(), "\n")
+	if len(units) != 1000 || strings.Trim(units, "abcABC") != "" {
+		t.Errorf("expected 1000 units of 3 types, actual %q", units)
+	}
+	if err := GeneratePolymer(&polymer, 1, 27, 1); err == nil {
+		t.Error("expected an error for 27 unit types")
+	}
+}
@@ -0,0 +1,136 @@
+package day05
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rules decides which pairs of adjacent units react and destroy each other.
+type Rules interface {
+	// React reports whether left reacts with right when it's immediately to
+	// the right's left.
+	React(left, right rune) bool
+}
+
+// CaseRules are the puzzle's rules: units react when they're the same letter
+// with opposite polarity (case). Letters are matched by Unicode case folding,
+// so e.g. 'ß' reacts with 'ẞ' and 'σ' and 'ς' both react with 'Σ'.
+type CaseRules struct{}
+
+// React implements Rules.
+func (CaseRules) React(left, right rune) bool {
+	if left < utf8.RuneSelf && right < utf8.RuneSelf {
+		// ASCII letters of opposite case only differ in one bit.
+		return left^right == 0x20 && 'a' <= left|0x20 && left|0x20 <= 'z'
+	}
+	if left == right || unicode.IsUpper(left) == unicode.IsUpper(right) {
+		return false
+	}
+	for folded := unicode.SimpleFold(left); folded != left; folded = unicode.SimpleFold(folded) {
+		if folded == right {
+			return true
+		}
+	}
+	return false
+}
+
+// PairRules react only the given pairs of units, in either order.
+type PairRules map[[2]rune]bool
+
+// React implements Rules.
+func (rules PairRules) React(left, right rune) bool {
+	return rules[[2]rune{left, right}] || rules[[2]rune{right, left}]
+}
+
+// ParsePairRules parses a rule set with one reacting pair per line, given as
+// two units separated by whitespace (e.g. "a X"). Blank lines are ignored.
+func ParsePairRules(input string) (PairRules, error) {
+	rules := PairRules{}
+	for i, line := range strings.Split(input, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 || utf8.RuneCountInString(fields[0]) != 1 || utf8.RuneCountInString(fields[1]) != 1 {
+			return rules, fmt.Errorf("line %d: expected two units, got %q", i+1, line)
+		}
+		left, _ := utf8.DecodeRuneInString(fields[0])
+		right, _ := utf8.DecodeRuneInString(fields[1])
+		rules[[2]rune{left, right}] = true
+	}
+	return rules, nil
+}
+
+// A Unit is a single unit of a polymer.
+type Unit struct {
+	Type rune
+	// Pos is the index of the unit in the original polymer, counted in runes.
+	Pos int
+}
+
+// MarshalJSON encodes the unit's type as a string rather than a code point.
+func (unit Unit) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		Pos  int    `json:"pos"`
+	}{string(unit.Type), unit.Pos})
+}
+
+// Reduce fully reacts the polymer under the given rules and returns the
+// surviving units.
+func Reduce(polymer string, rules Rules) []Unit {
+	return collapse(units(polymer), rules)
+}
+
+// String returns the polymer made up of the units.
+func String(units []Unit) string {
+	var builder strings.Builder
+	for _, unit := range units {
+		builder.WriteRune(unit.Type)
+	}
+	return builder.String()
+}
+
+func units(polymer string) []Unit {
+	units := make([]Unit, 0, len(polymer))
+	for _, char := range polymer {
+		units = append(units, Unit{Type: char, Pos: len(units)})
+	}
+	return units
+}
+
+// collapse returns the fully reacted polymer in a single pass. The units that
+// have survived so far are kept on a stack, so each new unit only needs to be
+// compared with the top of the stack. It doesn't modify units.
+func collapse(units []Unit, rules Rules) []Unit {
+	stack := make([]Unit, 0, len(units))
+	for _, unit := range units {
+		stack = push(stack, unit, rules)
+	}
+	return stack
+}
+
+// push adds unit to the top of the stack, or removes the top of the stack if
+// the two react.
+func push(stack []Unit, unit Unit, rules Rules) []Unit {
+	top := len(stack) - 1
+	if top >= 0 && rules.React(stack[top].Type, unit.Type) {
+		return stack[:top]
+	}
+	return append(stack, unit)
+}
+
+// foldKey returns the smallest rune that's equivalent to char under Unicode
+// case folding, which identifies its unit type regardless of polarity.
+func foldKey(char rune) rune {
+	key := char
+	for folded := unicode.SimpleFold(char); folded != char; folded = unicode.SimpleFold(folded) {
+		if folded < key {
+			key = folded
+		}
+	}
+	return key
+}
@@ -0,0 +1,161 @@
+package day05
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"unicode"
+	"unicode/utf8"
+)
+
+// progressInterval is the number of units between progress reports.
+const progressInterval = 1 << 24
+
+// A Reducer reduces a polymer one unit at a time, so the polymer never needs
+// to be held in memory. Only the units that have survived so far are kept, as
+// UTF-8, so an ASCII unit takes a single byte. Their positions take another 8
+// bytes each, so they're only kept if asked for.
+type Reducer struct {
+	rules Rules
+	stack []byte
+	// positions holds the position of each unit on the stack, if they're
+	// being tracked.
+	positions []int
+	surviving int
+	read      int
+}
+
+// NewReducer returns a Reducer for an empty polymer. The units' positions are
+// only tracked if trackPositions is set.
+func NewReducer(rules Rules, trackPositions bool) *Reducer {
+	reducer := &Reducer{rules: rules, stack: []byte{}}
+	if trackPositions {
+		reducer.positions = []int{}
+	}
+	return reducer
+}
+
+// Push adds the next unit of the polymer, reacting it with the polymer so far.
+func (reducer *Reducer) Push(char rune) {
+	top, size := utf8.DecodeLastRune(reducer.stack)
+	if size > 0 && reducer.rules.React(top, char) {
+		reducer.stack = reducer.stack[:len(reducer.stack)-size]
+		if reducer.positions != nil {
+			reducer.positions = reducer.positions[:len(reducer.positions)-1]
+		}
+		reducer.surviving--
+	} else {
+		reducer.stack = append(reducer.stack, string(char)...)
+		if reducer.positions != nil {
+			reducer.positions = append(reducer.positions, reducer.read)
+		}
+		reducer.surviving++
+	}
+	reducer.read++
+}
+
+// Read returns the number of units pushed so far.
+func (reducer *Reducer) Read() int {
+	return reducer.read
+}
+
+// Len returns the number of units that have survived so far.
+func (reducer *Reducer) Len() int {
+	return reducer.surviving
+}
+
+// String returns the polymer made up of the units that have survived so far.
+func (reducer *Reducer) String() string {
+	return string(reducer.stack)
+}
+
+// Units returns the units that have survived so far. Their positions are -1
+// unless the Reducer is tracking them.
+func (reducer *Reducer) Units() []Unit {
+	units := make([]Unit, 0, reducer.surviving)
+	for _, char := range string(reducer.stack) {
+		pos := -1
+		if reducer.positions != nil {
+			pos = reducer.positions[len(units)]
+		}
+		units = append(units, Unit{Type: char, Pos: pos})
+	}
+	return units
+}
+
+// Progress describes how far a streaming reduction has got.
+type Progress struct {
+	Bytes     int64
+	Units     int
+	Surviving int
+}
+
+// ReduceReader reduces the polymer read from reader, which must be UTF-8. Like
+// Part1, it only accepts letters, with optional whitespace before and after
+// them. If progress isn't nil, it's called periodically while reading and once
+// at the end.
+func ReduceReader(reader io.Reader, rules Rules, trackPositions bool, progress func(Progress)) (*Reducer, error) {
+	reducer := NewReducer(rules, trackPositions)
+	buffered := bufio.NewReaderSize(reader, 1<<16)
+	var bytes int64
+	report := func() {
+		if progress != nil {
+			progress(Progress{Bytes: bytes, Units: reducer.Read(), Surviving: reducer.Len()})
+		}
+	}
+	// The first whitespace after the polymer, which must be the end of it.
+	trailingSpace := rune(0)
+	for {
+		char, size, err := buffered.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return reducer, err
+		}
+		if char == utf8.RuneError && size == 1 {
+			return reducer, fmt.Errorf("invalid UTF-8 at byte %d", bytes)
+		}
+		bytes += int64(size)
+		switch {
+		case unicode.IsSpace(char):
+			if reducer.Read() > 0 && trailingSpace == 0 {
+				trailingSpace = char
+			}
+			continue
+		case trailingSpace != 0:
+			return reducer, fmt.Errorf("invalid character: '%c'", trailingSpace)
+		case !unicode.IsLetter(char):
+			return reducer, fmt.Errorf("invalid character: '%c'", char)
+		}
+		reducer.Push(char)
+		if reducer.Read()%progressInterval == 0 {
+			report()
+		}
+	}
+	report()
+	return reducer, nil
+}
+
+// GeneratePolymer writes a random polymer of the given length made up of the
+// first unitTypes letters of the alphabet, in either polarity.
+func GeneratePolymer(writer io.Writer, length int64, unitTypes int, seed int64) error {
+	if unitTypes < 1 || unitTypes > 26 {
+		return fmt.Errorf("can't generate %d unit types, expected 1 to 26", unitTypes)
+	}
+	random := rand.New(rand.NewSource(seed))
+	buffered := bufio.NewWriterSize(writer, 1<<16)
+	for i := int64(0); i < length; i++ {
+		unit := byte('a' + random.Intn(unitTypes))
+		if random.Intn(2) == 0 {
+			unit -= 'a' - 'A'
+		}
+		if err := buffered.WriteByte(unit); err != nil {
+			return err
+		}
+	}
+	if err := buffered.WriteByte('\n'); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
@@ -4,223 +4,258 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/orn688/advent-of-code-2018/internal/geom"
 )
 
-const infinity = -1
+// tie is the owner of a cell that's equally close to several points, which
+// counts towards nobody's area.
+const tie = -1
 
-type point struct {
-	X int
-	Y int
-}
+// The steps to a cell's neighbors.
+var neighborSteps = []geom.Point2{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}}
 
 // Part1 returns the largest area, defined as the constant Manhattan
 // distance-radius around one of the input points, that is not infinite.
 //
-// Important idea: keep track of the furthest left, right, top, and bottom
-// coordinates. If the area for a given point goes beyond one of those
-// coordinates, then its area is infinite.
+// Important idea: every cell outside the points' bounding box belongs to an
+// infinite area (or to nobody), so the finite areas can be measured within the
+// bounding box.
 func Part1(input string) (string, error) {
 	points, err := parseInput(input)
 	if err != nil {
 		return "", err
 	}
-	grid := plotAreas(points)
-	areas := findInfiniteAreas(grid)
-	for _, row := range grid {
-		for _, id := range row {
-			if areas[id] == infinity {
-				continue
-			}
-			areas[id]++
-		}
-	}
-	maxArea := 0
-	for _, area := range areas {
-		if area > maxArea {
-			maxArea = area
-		}
+	area, err := largestFiniteArea(points, Manhattan{})
+	if err != nil {
+		return "", err
 	}
-	return strconv.Itoa(maxArea), nil
+	return strconv.Itoa(area), nil
 }
 
+// DefaultMaxDist is the total distance to the points that cells in Part2's
+// region must be within.
+const DefaultMaxDist = 10000
+
 // Part2 returns the size of the region A such that for each point a in A, the
 // value sum(manhattanDist(a, p) for p in points) is less than 10,000.
-//
-// NOTE: this assumes that the region will be entirely contained within the
-// bounding box of the points, but this will not be true in all cases.
 func Part2(input string) (string, error) {
-	regionArea, err := centralAreaSize(input, 10000)
+	regionArea, err := SafeRegionSize(input, DefaultMaxDist, Manhattan{})
 	if err != nil {
 		return "", err
 	}
 	return strconv.Itoa(regionArea), nil
 }
 
-func centralAreaSize(input string, maxDist int) (int, error) {
+// SafeRegionSize returns the number of cells whose total distance to all of
+// the points is less than maxDist under the given metric.
+func SafeRegionSize(input string, maxDist int, metric Metric) (int, error) {
+	points, err := parseInput(input)
+	if err != nil {
+		return 0, err
+	}
+	return metric.centralAreaSize(points, maxDist), nil
+}
+
+// LargestFiniteArea returns the size of the largest area that isn't infinite
+// under the given metric.
+func LargestFiniteArea(input string, metric Metric) (int, error) {
 	points, err := parseInput(input)
 	if err != nil {
 		return 0, err
 	}
-	maxX, maxY, minX, minY := extremeCoords(points)
-	regionArea := 0
-	for x := minX; x <= maxX; x++ {
-		for y := minY; y <= maxY; y++ {
-			source := point{x, y}
-			if totalManhattanDistance(source, points) < maxDist {
-				regionArea++
+	return largestFiniteArea(points, metric)
+}
+
+// maxWindowScale is how many times bigger than the points' bounding box the
+// window used to measure the finite areas is allowed to get.
+const maxWindowScale = 9
+
+// largestFiniteArea measures the finite areas within a window around the
+// points. Under some metrics the finite areas can reach beyond the points'
+// bounding box, so the window grows until none of them reaches its edge. Under
+// SquaredEuclidean they can reach a very long way if some of the points are
+// nearly collinear, so it gives up once the window gets too big.
+func largestFiniteArea(points []geom.Point2, metric Metric) (int, error) {
+	infinite := metric.infiniteRegions(points)
+	window := geom.BoundingBox(points)
+	maxSize := maxWindowScale * maxInt(window.Width(), window.Height())
+	for {
+		grid := plotAreas(points, metric, window)
+		if !reachesEdge(grid, infinite) {
+			maxArea := 0
+			for id, area := range regionAreas(grid, len(points)) {
+				if !infinite[id] && area > maxArea {
+					maxArea = area
+				}
 			}
+			return maxArea, nil
+		}
+		window = window.Grow(maxInt(window.Width(), window.Height()))
+		if window.Width() > maxSize || window.Height() > maxSize {
+			return 0, fmt.Errorf("finite areas reach beyond a window %d wide around the points", maxSize)
+		}
+	}
+}
+
+// reachesEdge reports whether any of the finite areas owns a cell on the edge
+// of the grid.
+func reachesEdge(grid [][]int, infinite map[int]bool) bool {
+	maxY, maxX := len(grid)-1, len(grid[0])-1
+	isFinite := func(id int) bool {
+		return id != tie && !infinite[id]
+	}
+	for y := range grid {
+		if isFinite(grid[y][0]) || isFinite(grid[y][maxX]) {
+			return true
 		}
 	}
-	return regionArea, nil
+	for x := range grid[0] {
+		if isFinite(grid[0][x]) || isFinite(grid[maxY][x]) {
+			return true
+		}
+	}
+	return false
 }
 
-func parseInput(input string) ([]point, error) {
+func parseInput(input string) ([]geom.Point2, error) {
 	lines := strings.Split(strings.TrimSpace(input), "\n")
-	points := make([]point, len(lines))
+	points := make([]geom.Point2, len(lines))
 	for i, line := range lines {
-		rawCoords := strings.Split(line, ", ")
+		rawCoords := strings.Split(strings.TrimSpace(line), ", ")
 		if len(rawCoords) != 2 {
 			return points, fmt.Errorf("invalid line: %s", line)
 		}
-		rawX, rawY := rawCoords[0], rawCoords[1]
-		x, err := strconv.Atoi(rawX)
-		y, err := strconv.Atoi(rawY)
+		x, err := strconv.Atoi(rawCoords[0])
+		if err != nil {
+			return points, err
+		}
+		y, err := strconv.Atoi(rawCoords[1])
 		if err != nil {
 			return points, err
 		}
-		points[i] = point{x, y}
+		points[i] = geom.Point2{X: x, Y: y}
 	}
 	return points, nil
 }
 
-func plotAreas(points []point) [][]int {
-	points = normalize(points)
-	grid := plotPoints(points)
-	given := makeBoolGrid(len(grid), len(grid[0]))
-	for _, pt := range points {
-		given[pt.Y][pt.X] = true
+// plotAreas returns the owner of every cell in the window, which must contain
+// all of the points: the index of the closest point under the metric, or tie.
+// The grid is indexed by row and then column, relative to the window's corner.
+func plotAreas(points []geom.Point2, metric Metric, window geom.Rect) [][]int {
+	if _, ok := metric.(Manhattan); ok {
+		return plotManhattanAreas(points, window)
 	}
+	grid := makeIntGrid(window.Height(), window.Width())
 	for y := range grid {
-		for x := range grid[0] {
-			pt := point{x, y}
-			grid[y][x] = manhattanSearch(pt, grid, given)
+		for x := range grid[y] {
+			grid[y][x] = nearestPoint(window.Min.Add(geom.Point2{X: x, Y: y}), points, metric)
 		}
 	}
 	return grid
 }
 
-// Marks the area of all IDs with infinite area as -1.
-func findInfiniteAreas(grid [][]int) map[int]int {
-	infiniteIDs := make(map[int]int)
-	maxY, maxX := len(grid)-1, len(grid[0])-1
-	// Traverse the left and right edges.
-	for y := range grid {
-		leftID := grid[y][0]
-		rightID := grid[y][maxX]
-		infiniteIDs[leftID] = infinity
-		infiniteIDs[rightID] = infinity
-	}
-	// Traverse the top and bottom edges.
-	for x := range grid[0] {
-		topID := grid[0][x]
-		bottomID := grid[maxY][x]
-		infiniteIDs[topID] = infinity
-		infiniteIDs[bottomID] = infinity
-	}
-	return infiniteIDs
-}
-
-func normalize(points []point) []point {
-	normalized := make([]point, len(points))
-	_, _, minX, minY := extremeCoords(points)
-	for i, pt := range points {
-		normalized[i] = point{pt.X - minX, pt.Y - minY}
-	}
-	return normalized
-}
-
-func plotPoints(points []point) [][]int {
-	// Assumes points are already normalized, so minX and minY will be 0.
-	maxX, maxY, _, _ := extremeCoords(points)
-	grid := makeIntGrid(maxY+1, maxX+1)
+// nearestPoint returns the index of the point closest to source, or tie.
+func nearestPoint(source geom.Point2, points []geom.Point2, metric Metric) int {
+	owner, minDist := tie, 0
 	for i, pt := range points {
-		row, col := pt.Y, pt.X
-		grid[row][col] = i
+		dist := metric.Dist(source, pt)
+		switch {
+		case i == 0 || dist < minDist:
+			owner, minDist = i, dist
+		case dist == minDist:
+			owner = tie
+		}
 	}
-	return grid
+	return owner
 }
 
-// extremeCoords returns the extreme x and y values of the input points.
-// Assumes all x and y values are non-negative.
-func extremeCoords(points []point) (maxX int, maxY int, minX int, minY int) {
-	if len(points) == 0 {
-		return
-	}
-	maxX, maxY = points[0].X, points[0].Y
-	minX, minY = maxX, maxY
-	for _, pt := range points {
-		if pt.X > maxX {
-			maxX = pt.X
-		} else if pt.X < minX {
-			minX = pt.X
-		}
-		if pt.Y > maxY {
-			maxY = pt.Y
-		} else if pt.Y < minY {
-			minY = pt.Y
+// plotManhattanAreas is plotAreas for the Manhattan metric, without checking
+// the distance from every cell to every point.
+//
+// It's a breadth-first search from all of the points at once. Manhattan
+// distance is the length of the shortest path between cells, so a cell is
+// owned by a point if every neighbor one step closer to the points is owned by
+// it, and is a tie otherwise.
+func plotManhattanAreas(points []geom.Point2, window geom.Rect) [][]int {
+	bounds := geom.Rect{Max: window.Max.Sub(window.Min)}
+	grid := makeIntGrid(bounds.Height(), bounds.Width())
+	dists := makeIntGrid(bounds.Height(), bounds.Width())
+	for _, row := range dists {
+		for x := range row {
+			row[x] = -1
 		}
 	}
-	return
-}
 
-func makeIntGrid(rows int, columns int) [][]int {
-	grid := make([][]int, rows)
-	for i := range grid {
-		grid[i] = make([]int, columns)
+	queue := []geom.Point2{}
+	for i, pt := range points {
+		pt = pt.Sub(window.Min)
+		if dists[pt.Y][pt.X] == 0 {
+			// Duplicate points are tied everywhere.
+			grid[pt.Y][pt.X] = tie
+			continue
+		}
+		dists[pt.Y][pt.X] = 0
+		grid[pt.Y][pt.X] = i
+		queue = append(queue, pt)
 	}
-	return grid
-}
-
-func makeBoolGrid(rows int, columns int) [][]bool {
-	grid := make([][]bool, rows)
-	for i := range grid {
-		grid[i] = make([]bool, columns)
+	for head := 0; head < len(queue); head++ {
+		pt := queue[head]
+		for _, step := range neighborSteps {
+			next := pt.Add(step)
+			if !bounds.Contains(next) {
+				continue
+			}
+			switch dists[next.Y][next.X] {
+			case -1:
+				dists[next.Y][next.X] = dists[pt.Y][pt.X] + 1
+				grid[next.Y][next.X] = grid[pt.Y][pt.X]
+				queue = append(queue, next)
+			case dists[pt.Y][pt.X] + 1:
+				if grid[next.Y][next.X] != grid[pt.Y][pt.X] {
+					grid[next.Y][next.X] = tie
+				}
+			}
+		}
 	}
 	return grid
 }
 
-// Returns the ID of the given point in the grid that has the closest Manhattan
-// distance to the source point.
-func manhattanSearch(source point, grid [][]int, given [][]bool) int {
-	for dist := 0; true; dist++ {
-		for xDist := -dist; xDist <= dist; xDist++ {
-			yDists := []int{dist - abs(xDist), -dist + abs(xDist)}
-			for _, yDist := range yDists {
-				x, y := source.X+xDist, source.Y+yDist
-				outOfRange := y < 0 || y >= len(given) || x < 0 || x >= len(given[0])
-				if !outOfRange && given[y][x] {
-					return grid[y][x]
-				}
+// regionAreas returns the number of cells in the grid owned by each of the
+// points.
+func regionAreas(grid [][]int, numPoints int) []int {
+	areas := make([]int, numPoints)
+	for _, row := range grid {
+		for _, id := range row {
+			if id != tie {
+				areas[id]++
 			}
 		}
 	}
-	return -1
+	return areas
 }
 
-func totalManhattanDistance(source point, points []point) (totalDist int) {
-	for _, dest := range points {
-		totalDist += manhattanDistance(source, dest)
+// ownerAtInfinity returns the index of the point with the smallest distance
+// key, or tie if there isn't a unique one. The key is a point's distance from
+// a far away cell, less the distance that's the same for every point.
+func ownerAtInfinity(points []geom.Point2, distKey func(geom.Point2) int) int {
+	owner, minKey := tie, 0
+	for i, pt := range points {
+		key := distKey(pt)
+		switch {
+		case i == 0 || key < minKey:
+			owner, minKey = i, key
+		case key == minKey:
+			owner = tie
+		}
 	}
-	return
-}
-
-func manhattanDistance(source point, dest point) int {
-	return abs(dest.X-source.X) + abs(dest.Y-source.Y)
+	return owner
 }
 
-func abs(x int) int {
-	if x < 0 {
-		return -x
+func makeIntGrid(rows int, columns int) [][]int {
+	grid := make([][]int, rows)
+	for i := range grid {
+		grid[i] = make([]int, columns)
 	}
-	return x
+	return grid
 }
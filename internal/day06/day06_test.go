@@ -1,8 +1,15 @@
 package day06
 
 import (
-	"strconv"
+	"fmt"
+	"image/color"
+	"math/rand"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/orn688/advent-of-code-2018/internal/geom"
+	"github.com/orn688/advent-of-code-2018/internal/testutil"
 )
 
 var input = `
@@ -15,17 +22,305 @@ var input = `
 `
 
 func TestPart1(t *testing.T) {
-	expected := 17
-	actual, _ := Part1(input)
-	if actual != strconv.Itoa(expected) {
-		t.Errorf("expected %d, actual %s", expected, actual)
-	}
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Name: "example", Input: input, Expected: "17"},
+		// The cell at 2, 0 is tied between the four inner points, so it
+		// doesn't count towards any of their areas.
+		{Name: "ties", Input: "0, 0\n4, 0\n2, 2\n2, -2\n6, 0\n2, 4\n-2, 0\n2, -4", Expected: "4"},
+	})
 }
 
 func TestPart2(t *testing.T) {
 	expected := 16
-	actual, _ := centralAreaSize(input, 32)
+	actual, _ := SafeRegionSize(input, 32, Manhattan{})
+	if actual != expected {
+		t.Errorf("expected %d, actual %d", expected, actual)
+	}
+}
+
+func TestCentralAreaSizeBeyondBounds(t *testing.T) {
+	// The region is a diamond around the only point.
+	expected := 1 + 4 + 8
+	actual := Manhattan{}.centralAreaSize([]geom.Point2{{X: 5, Y: -2}}, 3)
+	if actual != expected {
+		t.Errorf("expected %d, actual %d", expected, actual)
+	}
+	// A disc of radius 100, which only needs a small window to be scanned.
+	expected = 0
+	for x := -100; x <= 100; x++ {
+		for y := -100; y <= 100; y++ {
+			if x*x+y*y < 10000 {
+				expected++
+			}
+		}
+	}
+	actual = SquaredEuclidean{}.centralAreaSize([]geom.Point2{{X: 0, Y: 0}}, 10000)
 	if actual != expected {
 		t.Errorf("expected %d, actual %d", expected, actual)
 	}
 }
+
+func TestIsqrt(t *testing.T) {
+	for n, expected := range map[int]int{-1: 0, 0: 0, 1: 1, 3: 1, 4: 2, 99: 9, 100: 10, 1 << 52: 1 << 26} {
+		if actual := isqrt(n); actual != expected {
+			t.Errorf("%d: expected %d, actual %d", n, expected, actual)
+		}
+	}
+}
+
+func TestCentralAreaSizeMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		points := randomPoints(random, 5, 10)
+		maxDist := random.Intn(60)
+		for _, metric := range append(metrics, SquaredEuclidean{}) {
+			expected := bruteForceCentralAreaSize(points, maxDist, metric)
+			if actual := metric.centralAreaSize(points, maxDist); actual != expected {
+				t.Fatalf("%T %v within %d: expected %d, actual %d", metric, points, maxDist, expected, actual)
+			}
+		}
+	}
+}
+
+func TestPlotAreasTies(t *testing.T) {
+	points := []geom.Point2{{X: 0, Y: 0}, {X: 2, Y: 2}}
+	expected := [][]int{
+		{0, 0, tie},
+		{0, tie, 1},
+		{tie, 1, 1},
+	}
+	if actual := plotAreas(points, Manhattan{}, geom.BoundingBox(points)); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+}
+
+func TestInfiniteRegions(t *testing.T) {
+	points, _ := parseInput(input)
+	expected := map[int]bool{0: true, 1: true, 2: true, 5: true}
+	if actual := (Manhattan{}).infiniteRegions(points); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+}
+
+// metrics are the metrics whose finite areas stay close to the points, so
+// their infinite areas can be found by brute force just beyond the points'
+// bounding box.
+var metrics = []Metric{Manhattan{}, WeightedManhattan{X: 2, Y: 3}, Chebyshev{}}
+
+func randomPoints(random *rand.Rand, maxPoints int, size int) []geom.Point2 {
+	points := make([]geom.Point2, 1+random.Intn(maxPoints))
+	for i := range points {
+		points[i] = geom.Point2{X: random.Intn(size) - size/2, Y: random.Intn(size) - size/2}
+	}
+	return points
+}
+
+func TestMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		points := randomPoints(random, 12, 20)
+		bounds := geom.BoundingBox(points)
+
+		grid := plotAreas(points, Manhattan{}, bounds)
+		for _, pt := range bounds.Points() {
+			cell := pt.Sub(bounds.Min)
+			if expected, actual := nearestPoint(pt, points, Manhattan{}), grid[cell.Y][cell.X]; actual != expected {
+				t.Fatalf("%v: cell %v: expected %d, actual %d", points, pt, expected, actual)
+			}
+		}
+
+		for _, metric := range metrics {
+			expected := bruteForceInfiniteRegions(points, metric, 50)
+			if actual := metric.infiniteRegions(points); !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("%T %v: expected %v, actual %v", metric, points, expected, actual)
+			}
+		}
+		// A finite area under SquaredEuclidean can reach as far as the
+		// circumcentre of three of the points, which for nearly collinear
+		// points can be a long way off.
+		expected := bruteForceInfiniteRegions(points, SquaredEuclidean{}, 20000)
+		if actual := (SquaredEuclidean{}).infiniteRegions(points); !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("%T %v: expected %v, actual %v", SquaredEuclidean{}, points, expected, actual)
+		}
+	}
+}
+
+func TestSquaredEuclideanInfiniteRegions(t *testing.T) {
+	for _, example := range []struct {
+		points   []geom.Point2
+		expected map[int]bool
+	}{
+		{
+			// The last point is inside the triangle, and the one before is in
+			// the middle of an edge.
+			points:   []geom.Point2{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 10}, {X: 5, Y: 5}, {X: 2, Y: 3}},
+			expected: map[int]bool{0: true, 1: true, 2: true, 3: true},
+		},
+		{
+			points:   []geom.Point2{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 3, Y: 3}},
+			expected: map[int]bool{0: true, 1: true, 2: true},
+		},
+		{
+			points:   []geom.Point2{{X: 4, Y: 4}},
+			expected: map[int]bool{0: true},
+		},
+		{
+			// The duplicated corner is tied everywhere.
+			points:   []geom.Point2{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 4}},
+			expected: map[int]bool{0: true, 3: true},
+		},
+	} {
+		if actual := (SquaredEuclidean{}).infiniteRegions(example.points); !reflect.DeepEqual(actual, example.expected) {
+			t.Errorf("%v: expected %v, actual %v", example.points, example.expected, actual)
+		}
+	}
+}
+
+func TestLargestFiniteAreaBeyondBounds(t *testing.T) {
+	// The last point's area reaches far below the others.
+	points := []geom.Point2{{X: 0, Y: 0}, {X: 20, Y: 0}, {X: 10, Y: 20}, {X: 10, Y: 1}}
+	metric := SquaredEuclidean{}
+	expected := regionAreas(plotAreas(points, metric, geom.BoundingBox(points).Grow(100)), len(points))[3]
+	if actual, err := largestFiniteArea(points, metric); err != nil || actual != expected {
+		t.Errorf("expected %d, actual %d (%v)", expected, actual, err)
+	}
+
+	// Here the area reaches down to the circumcentre of the first three
+	// points, at (20, -199.5), which is too far to look.
+	points = []geom.Point2{{X: 0, Y: 0}, {X: 40, Y: 0}, {X: 20, Y: 1}, {X: 20, Y: 2}}
+	if _, err := largestFiniteArea(points, metric); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestParseMetric(t *testing.T) {
+	for name, expected := range map[string]Metric{
+		"manhattan":         Manhattan{},
+		"chebyshev":         Chebyshev{},
+		"squared-euclidean": SquaredEuclidean{},
+		"weighted:2,5":      WeightedManhattan{X: 2, Y: 5},
+	} {
+		if actual, err := ParseMetric(name); err != nil || actual != expected {
+			t.Errorf("%s: expected %v, actual %v (%v)", name, expected, actual, err)
+		}
+	}
+	for _, name := range []string{"euclid", "weighted:1", "weighted:0,1", "weighted:a,b"} {
+		if _, err := ParseMetric(name); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// bruteForceInfiniteRegions returns the owners of the cells around the edge of
+// the points' bounding box grown by margin.
+func bruteForceInfiniteRegions(points []geom.Point2, metric Metric, margin int) map[int]bool {
+	big := geom.BoundingBox(points).Grow(margin)
+	infinite := make(map[int]bool)
+	addOwner := func(pt geom.Point2) {
+		if owner := nearestPoint(pt, points, metric); owner != tie {
+			infinite[owner] = true
+		}
+	}
+	for x := big.Min.X; x < big.Max.X; x++ {
+		addOwner(geom.Point2{X: x, Y: big.Min.Y})
+		addOwner(geom.Point2{X: x, Y: big.Max.Y - 1})
+	}
+	for y := big.Min.Y; y < big.Max.Y; y++ {
+		addOwner(geom.Point2{X: big.Min.X, Y: y})
+		addOwner(geom.Point2{X: big.Max.X - 1, Y: y})
+	}
+	return infinite
+}
+
+// bruteForceCentralAreaSize checks every cell that's close enough to the first
+// point to possibly be in the region.
+func bruteForceCentralAreaSize(points []geom.Point2, maxDist int, metric Metric) int {
+	regionArea := 0
+	for x := points[0].X - maxDist; x <= points[0].X+maxDist; x++ {
+		for y := points[0].Y - maxDist; y <= points[0].Y+maxDist; y++ {
+			totalDist := 0
+			for _, pt := range points {
+				totalDist += metric.Dist(pt, geom.Point2{X: x, Y: y})
+			}
+			if totalDist < maxDist {
+				regionArea++
+			}
+		}
+	}
+	return regionArea
+}
+
+func TestPartitionRows(t *testing.T) {
+	partition, err := NewPartition(input, Manhattan{}, 0, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The example from the puzzle, cropped to the bounding box.
+	testutil.AssertGridEqual(t, `
+Aaaa.ccc
+aaddeccc
+adddeccC
+.dDdeecc
+b.deEeec
+Bb.eeee.
+bb.eeeff
+bb.eefff
+bb.ffffF
+`, strings.Join(partition.Rows(), "\n"))
+}
+
+func TestImage(t *testing.T) {
+	partition, err := NewPartition(input, Manhattan{}, 1, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := partition.Image()
+	if size := img.Bounds().Size(); size.X != 10 || size.Y != 11 {
+		t.Errorf("expected a 10x11 image, actual %v", size)
+	}
+	testcases := []struct {
+		x, y     int
+		expected color.RGBA
+	}{
+		// The window starts at 0, 0.
+		{0, 0, areaColor(0, true)},
+		{1, 1, pointColor},
+		{5, 0, tieColor},
+		// E is finite, and 5, 4 is inside the safe region.
+		{6, 6, areaColor(4, false)},
+		{5, 4, areaColor(4, false)},
+		{3, 3, outlineColor},
+	}
+	for _, tc := range testcases {
+		if actual := color.RGBAModel.Convert(img.At(tc.x, tc.y)); actual != tc.expected {
+			t.Errorf("(%d,%d): expected %v, actual %v", tc.x, tc.y, tc.expected, actual)
+		}
+	}
+	if areaColor(0, false) == areaColor(1, false) {
+		t.Errorf("expected different colors for different areas")
+	}
+}
+
+func TestWriteANSI(t *testing.T) {
+	// Only B is in the safe region.
+	partition, err := NewPartition("0, 0\n1, 0\n3, 0", Manhattan{}, 0, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ansi strings.Builder
+	if err := partition.WriteANSI(&ansi); err != nil {
+		t.Fatal(err)
+	}
+	a, b, tied, c := areaColor(0, true), areaColor(1, true), tieColor, areaColor(2, true)
+	expected := fmt.Sprintf("\x1b[30;48;2;%d;%d;%dmA\x1b[30;48;2;%d;%d;%d;4mB"+
+		"\x1b[30;48;2;%d;%d;%dm.\x1b[30;48;2;%d;%d;%dmC\x1b[0m\n",
+		a.R, a.G, a.B, b.R, b.G, b.B, tied.R, tied.G, tied.B, c.R, c.G, c.B)
+	if actual := ansi.String(); actual != expected {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+	testutil.AssertWriteErrors(t, partition.WriteANSI)
+
+	if _, err := NewPartition("0, 0\n1, 0\n3, 0", Manhattan{}, -10, 4); err == nil {
+		t.Error("expected an error for a negative margin")
+	}
+}
@@ -0,0 +1,361 @@
+package day06
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/orn688/advent-of-code-2018/internal/geom"
+)
+
+// A Metric measures the distance between two cells. Each metric knows which
+// areas are infinite under it and how to measure its safe region, since
+// neither can be found just by looking at a finite part of the plane.
+type Metric interface {
+	Dist(p, q geom.Point2) int
+	// infiniteRegions returns the indexes of the points whose areas are
+	// infinite.
+	infiniteRegions(points []geom.Point2) map[int]bool
+	// centralAreaSize counts the cells whose total distance to the points is
+	// less than maxDist.
+	centralAreaSize(points []geom.Point2, maxDist int) int
+}
+
+// MetricNames describes the metrics understood by ParseMetric.
+const MetricNames = "manhattan, chebyshev, squared-euclidean or weighted:X,Y"
+
+// ParseMetric returns the metric with the given name, one of MetricNames.
+func ParseMetric(name string) (Metric, error) {
+	switch name {
+	case "manhattan":
+		return Manhattan{}, nil
+	case "chebyshev":
+		return Chebyshev{}, nil
+	case "squared-euclidean":
+		return SquaredEuclidean{}, nil
+	}
+	if rawWeights := strings.TrimPrefix(name, "weighted:"); rawWeights != name {
+		weights := strings.Split(rawWeights, ",")
+		if len(weights) == 2 {
+			x, errX := strconv.Atoi(weights[0])
+			y, errY := strconv.Atoi(weights[1])
+			if errX == nil && errY == nil && x > 0 && y > 0 {
+				return WeightedManhattan{X: x, Y: y}, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid weights %q, expected two positive integers", rawWeights)
+	}
+	return nil, fmt.Errorf("unknown metric %q, expected %s", name, MetricNames)
+}
+
+// Manhattan is the taxicab distance, which the puzzle uses.
+type Manhattan struct{}
+
+// Dist implements Metric.
+func (Manhattan) Dist(p, q geom.Point2) int {
+	return p.Manhattan(q)
+}
+
+func (Manhattan) infiniteRegions(points []geom.Point2) map[int]bool {
+	return WeightedManhattan{X: 1, Y: 1}.infiniteRegions(points)
+}
+
+func (Manhattan) centralAreaSize(points []geom.Point2, maxDist int) int {
+	return WeightedManhattan{X: 1, Y: 1}.centralAreaSize(points, maxDist)
+}
+
+// WeightedManhattan is the taxicab distance with each step along the x axis
+// costing X and each step along the y axis costing Y. Both must be positive.
+type WeightedManhattan struct {
+	X int
+	Y int
+}
+
+// Dist implements Metric.
+func (metric WeightedManhattan) Dist(p, q geom.Point2) int {
+	return metric.X*geom.Abs(p.X-q.X) + metric.Y*geom.Abs(p.Y-q.Y)
+}
+
+// infiniteRegions relies on moving further along a row (or column) past the
+// points' bounding box adding the same distance to every point, so the row's
+// cells all have the same owner. A point's area is infinite exactly when it
+// owns the far end of some row or column. Rows and columns beyond the bounding
+// box all have the same owners as the ones just beyond it, which stand for the
+// corners.
+func (metric WeightedManhattan) infiniteRegions(points []geom.Point2) map[int]bool {
+	bounds := geom.BoundingBox(points)
+	infinite := make(map[int]bool)
+	for _, dir := range []int{1, -1} {
+		for y := bounds.Min.Y - 1; y <= bounds.Max.Y; y++ {
+			owner := ownerAtInfinity(points, func(pt geom.Point2) int {
+				return metric.Y*geom.Abs(y-pt.Y) - dir*metric.X*pt.X
+			})
+			if owner != tie {
+				infinite[owner] = true
+			}
+		}
+		for x := bounds.Min.X - 1; x <= bounds.Max.X; x++ {
+			owner := ownerAtInfinity(points, func(pt geom.Point2) int {
+				return metric.X*geom.Abs(x-pt.X) - dir*metric.Y*pt.Y
+			})
+			if owner != tie {
+				infinite[owner] = true
+			}
+		}
+	}
+	return infinite
+}
+
+// centralAreaSize relies on a cell's total distance being the sum of its
+// total distances along each axis, which are found for every column and row
+// that could be in the region. The cells are then counted by pairing each
+// column's total with the rows' totals that are small enough to go with it.
+func (metric WeightedManhattan) centralAreaSize(points []geom.Point2, maxDist int) int {
+	xs := make([]int, len(points))
+	ys := make([]int, len(points))
+	for i, pt := range points {
+		xs[i], ys[i] = pt.X, pt.Y
+	}
+	xTotals := axisTotals(xs, metric.X, maxDist)
+	yTotals := axisTotals(ys, metric.Y, maxDist)
+
+	regionArea := 0
+	rows := len(yTotals)
+	for _, xTotal := range xTotals {
+		for rows > 0 && xTotal+yTotals[rows-1] >= maxDist {
+			rows--
+		}
+		regionArea += rows
+	}
+	return regionArea
+}
+
+// axisTotals returns the total distances along one axis, in ascending order,
+// from every position to the given coordinates that are less than maxDist.
+// Each step along the axis costs weight.
+//
+// Each step further than maxDist/(len(coords)*weight) beyond the outermost
+// coordinate adds len(coords)*weight to the total, so the positions that count
+// are within that distance of the coordinates.
+func axisTotals(coords []int, weight int, maxDist int) []int {
+	sorted := append([]int{}, coords...)
+	sort.Ints(sorted)
+	margin := maxDist/(len(sorted)*weight) + 1
+	start, end := sorted[0]-margin, sorted[len(sorted)-1]+margin
+
+	total := 0
+	for _, coord := range sorted {
+		total += coord - start
+	}
+	totals := []int{}
+	before := 0 // the number of coordinates at or before pos
+	for pos := start; pos <= end; pos++ {
+		for before < len(sorted) && sorted[before] <= pos {
+			before++
+		}
+		if total*weight < maxDist {
+			totals = append(totals, total*weight)
+		}
+		// Moving on to pos+1 takes it further from the coordinates before it
+		// and closer to the others.
+		total += before - (len(sorted) - before)
+	}
+	sort.Ints(totals)
+	return totals
+}
+
+// Chebyshev is the chessboard distance, where diagonal steps cost the same as
+// straight ones.
+type Chebyshev struct{}
+
+// Dist implements Metric.
+func (Chebyshev) Dist(p, q geom.Point2) int {
+	return p.Chebyshev(q)
+}
+
+// infiniteRegions looks at each quadrant in turn, reflected so that it's the
+// one with large x and y. Once a cell (x, x+c) on the diagonal line c is past
+// all of the points, its distance to the point q is x+max(-q.X, c-q.Y), so
+// every diagonal has the same owner from then on. Diagonals far enough to
+// either side of the points all have the same owner as the axis they approach.
+func (Chebyshev) infiniteRegions(points []geom.Point2) map[int]bool {
+	infinite := make(map[int]bool)
+	reflected := make([]geom.Point2, len(points))
+	for _, sx := range []int{1, -1} {
+		for _, sy := range []int{1, -1} {
+			for i, pt := range points {
+				reflected[i] = geom.Point2{X: sx * pt.X, Y: sy * pt.Y}
+			}
+			minC, maxC := reflected[0].Y-reflected[0].X, reflected[0].Y-reflected[0].X
+			for _, pt := range reflected {
+				minC = minInt(minC, pt.Y-pt.X)
+				maxC = maxInt(maxC, pt.Y-pt.X)
+			}
+			for c := minC - 1; c <= maxC+1; c++ {
+				owner := ownerAtInfinity(reflected, func(pt geom.Point2) int {
+					return maxInt(-pt.X, c-pt.Y)
+				})
+				if owner != tie {
+					infinite[owner] = true
+				}
+			}
+		}
+	}
+	return infinite
+}
+
+// centralAreaSize scans every cell that could be in the region. Each step
+// beyond the points' bounding box adds at least len(points) to the total
+// distance.
+func (metric Chebyshev) centralAreaSize(points []geom.Point2, maxDist int) int {
+	return scanCentralAreaSize(points, maxDist, metric, maxDist/len(points)+1)
+}
+
+// SquaredEuclidean is the square of the straight-line distance. It partitions
+// the plane the same way as the straight-line distance, but it's exact.
+type SquaredEuclidean struct{}
+
+// Dist implements Metric.
+func (SquaredEuclidean) Dist(p, q geom.Point2) int {
+	return p.SquaredEuclidean(q)
+}
+
+// infiniteRegions relies on a point's area being infinite exactly when it's
+// on the boundary of the points' convex hull: a hull vertex owns a cone that
+// widens without limit, and a point in the middle of a hull edge owns a strip
+// that contains a whole line of cells. Points inside the hull are surrounded.
+// A point that's given more than once doesn't own anything.
+func (SquaredEuclidean) infiniteRegions(points []geom.Point2) map[int]bool {
+	counts := make(map[geom.Point2]int)
+	for _, pt := range points {
+		counts[pt]++
+	}
+	hull := convexHull(points)
+	infinite := make(map[int]bool)
+	for i, pt := range points {
+		if counts[pt] == 1 && onHullBoundary(pt, hull) {
+			infinite[i] = true
+		}
+	}
+	return infinite
+}
+
+// centralAreaSize scans every cell that could be in the region. A cell d steps
+// beyond the points' bounding box is at least d*d from each of the points, so
+// the region ends within the square root of maxDist/len(points).
+func (metric SquaredEuclidean) centralAreaSize(points []geom.Point2, maxDist int) int {
+	return scanCentralAreaSize(points, maxDist, metric, isqrt(maxDist/len(points))+1)
+}
+
+// scanCentralAreaSize counts the cells in the region by checking every cell
+// within margin of the points' bounding box, which must contain the region.
+func scanCentralAreaSize(points []geom.Point2, maxDist int, metric Metric, margin int) int {
+	regionArea := 0
+	window := geom.BoundingBox(points).Grow(margin)
+	for y := window.Min.Y; y < window.Max.Y; y++ {
+		for x := window.Min.X; x < window.Max.X; x++ {
+			cell := geom.Point2{X: x, Y: y}
+			totalDist := 0
+			for _, pt := range points {
+				totalDist += metric.Dist(cell, pt)
+			}
+			if totalDist < maxDist {
+				regionArea++
+			}
+		}
+	}
+	return regionArea
+}
+
+// convexHull returns the vertices of the points' convex hull in
+// counterclockwise order, leaving out any in the middle of an edge. All of the
+// points are returned if there are fewer than two distinct ones.
+func convexHull(points []geom.Point2) []geom.Point2 {
+	sorted := append([]geom.Point2{}, points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+	unique := sorted[:0]
+	for i, pt := range sorted {
+		if i == 0 || pt != sorted[i-1] {
+			unique = append(unique, pt)
+		}
+	}
+	if len(unique) < 2 {
+		return unique
+	}
+
+	// Andrew's monotone chain: build the lower hull left to right, then the
+	// upper hull right to left.
+	hull := []geom.Point2{}
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, pt := range unique {
+			for len(hull) >= start+2 && cross(hull[len(hull)-2], hull[len(hull)-1], pt) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, pt)
+		}
+		// The last point is the first point of the other half.
+		hull = hull[:len(hull)-1]
+		for i, j := 0, len(unique)-1; i < j; i, j = i+1, j-1 {
+			unique[i], unique[j] = unique[j], unique[i]
+		}
+	}
+	return hull
+}
+
+// cross returns the z component of the cross product of b-a and c-a, which
+// is positive if a, b, c turn counterclockwise and 0 if they're collinear.
+func cross(a, b, c geom.Point2) int {
+	ab, ac := b.Sub(a), c.Sub(a)
+	return ab.X*ac.Y - ab.Y*ac.X
+}
+
+func onHullBoundary(pt geom.Point2, hull []geom.Point2) bool {
+	if len(hull) == 1 {
+		return pt == hull[0]
+	}
+	for i, a := range hull {
+		b := hull[(i+1)%len(hull)]
+		if cross(a, b, pt) == 0 && geom.BoundingBox([]geom.Point2{a, b}).Contains(pt) {
+			return true
+		}
+	}
+	return false
+}
+
+// isqrt returns the largest integer whose square is at most n, or 0 if n is
+// negative.
+func isqrt(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	root := int(math.Sqrt(float64(n)))
+	for root*root > n {
+		root--
+	}
+	for (root+1)*(root+1) <= n {
+		root++
+	}
+	return root
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
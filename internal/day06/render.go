@@ -0,0 +1,206 @@
+package day06
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"unicode"
+
+	"github.com/orn688/advent-of-code-2018/internal/geom"
+)
+
+var (
+	tieColor     = color.RGBA{0x80, 0x80, 0x80, 0xff}
+	pointColor   = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	outlineColor = color.RGBA{0x00, 0x00, 0x00, 0xff}
+)
+
+// areaColor returns the color of a point's area. The hues are spread around
+// the color wheel by the golden angle, so that points with nearby indexes get
+// very different colors. Infinite areas are darker.
+func areaColor(owner int, infinite bool) color.RGBA {
+	hue := math.Mod(float64(owner)*137.508, 360) / 60
+	saturation, value := 0.55, 0.95
+	if infinite {
+		value = 0.5
+	}
+	// Convert from HSV to RGB.
+	chroma := value * saturation
+	secondary := chroma * (1 - math.Abs(math.Mod(hue, 2)-1))
+	var r, g, b float64
+	switch int(hue) {
+	case 0:
+		r, g = chroma, secondary
+	case 1:
+		r, g = secondary, chroma
+	case 2:
+		g, b = chroma, secondary
+	case 3:
+		g, b = secondary, chroma
+	case 4:
+		r, b = secondary, chroma
+	default:
+		r, b = chroma, secondary
+	}
+	m := value - chroma
+	return color.RGBA{
+		R: uint8(math.Round((r + m) * 0xff)),
+		G: uint8(math.Round((g + m) * 0xff)),
+		B: uint8(math.Round((b + m) * 0xff)),
+		A: 0xff,
+	}
+}
+
+// A Partition records which point owns every cell in a window around the
+// points.
+type Partition struct {
+	Points []geom.Point2
+	Window geom.Rect
+	// Owners holds the index of the point closest to each cell, or -1 for a
+	// tie, by row and then column relative to the window's corner.
+	Owners [][]int
+	// Infinite holds the indexes of the points whose areas are infinite.
+	Infinite map[int]bool
+	// Safe marks the cells whose total distance to the points is less than
+	// the partition's maxDist, like Owners.
+	Safe [][]bool
+}
+
+// NewPartition partitions the points' bounding box, grown by margin on every
+// side, under the given metric. Cells whose total distance to the points is
+// less than maxDist are part of the safe region. The margin can't be negative.
+func NewPartition(input string, metric Metric, margin int, maxDist int) (*Partition, error) {
+	if margin < 0 {
+		return nil, fmt.Errorf("invalid margin: %d", margin)
+	}
+	points, err := parseInput(input)
+	if err != nil {
+		return nil, err
+	}
+	window := geom.BoundingBox(points).Grow(margin)
+	safe := make([][]bool, window.Height())
+	for y := range safe {
+		safe[y] = make([]bool, window.Width())
+	}
+	for y := range safe {
+		for x := range safe[y] {
+			cell := window.Min.Add(geom.Point2{X: x, Y: y})
+			totalDist := 0
+			for _, pt := range points {
+				totalDist += metric.Dist(cell, pt)
+			}
+			safe[y][x] = totalDist < maxDist
+		}
+	}
+	return &Partition{
+		Points:   points,
+		Window:   window,
+		Owners:   plotAreas(points, metric, window),
+		Infinite: metric.infiniteRegions(points),
+		Safe:     safe,
+	}, nil
+}
+
+// cellColor returns the color of the cell at x, y relative to the window.
+func (partition *Partition) cellColor(x, y int) color.RGBA {
+	owner := partition.Owners[y][x]
+	if owner == tie {
+		return tieColor
+	}
+	return areaColor(owner, partition.Infinite[owner])
+}
+
+// onSafeEdge reports whether the cell at x, y relative to the window is part
+// of the safe region but next to a cell that isn't.
+func (partition *Partition) onSafeEdge(x, y int) bool {
+	if !partition.Safe[y][x] {
+		return false
+	}
+	for _, step := range neighborSteps {
+		next := geom.Point2{X: x, Y: y}.Add(step)
+		inWindow := next.X >= 0 && next.X < len(partition.Safe[0]) && next.Y >= 0 && next.Y < len(partition.Safe)
+		if inWindow && !partition.Safe[next.Y][next.X] {
+			return true
+		}
+	}
+	return false
+}
+
+// Image draws the partition with one pixel per cell. Each area has its own
+// color, which is darker if the area is infinite, and ties are gray. The
+// points themselves are white, and the safe region is outlined in black.
+func (partition *Partition) Image() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, partition.Window.Width(), partition.Window.Height()))
+	for y, owners := range partition.Owners {
+		for x := range owners {
+			if partition.onSafeEdge(x, y) {
+				img.SetRGBA(x, y, outlineColor)
+			} else {
+				img.SetRGBA(x, y, partition.cellColor(x, y))
+			}
+		}
+	}
+	for _, pt := range partition.Points {
+		pt = pt.Sub(partition.Window.Min)
+		img.SetRGBA(pt.X, pt.Y, pointColor)
+	}
+	return img
+}
+
+// WritePNG writes the partition drawn by Image as a PNG.
+func (partition *Partition) WritePNG(w io.Writer) error {
+	return png.Encode(w, partition.Image())
+}
+
+// WriteANSI draws the partition for a terminal, as Rows but with each cell's
+// background colored like Image. The safe region is underlined.
+func (partition *Partition) WriteANSI(w io.Writer) error {
+	for y, row := range partition.Rows() {
+		for x, label := range []rune(row) {
+			c := partition.cellColor(x, y)
+			style := ""
+			if partition.Safe[y][x] {
+				style = ";4"
+			}
+			if _, err := fmt.Fprintf(w, "\x1b[30;48;2;%d;%d;%d%sm%c", c.R, c.G, c.B, style, label); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w, "\x1b[0m"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rows draws the partition like the puzzle does, with a lowercase letter for
+// each cell, an uppercase one for the points themselves and a dot for ties.
+// The letters repeat after 26 points.
+func (partition *Partition) Rows() []string {
+	rows := make([][]rune, len(partition.Owners))
+	for y, owners := range partition.Owners {
+		rows[y] = make([]rune, len(owners))
+		for x, owner := range owners {
+			rows[y][x] = ownerLabel(owner)
+		}
+	}
+	for i, pt := range partition.Points {
+		cell := pt.Sub(partition.Window.Min)
+		rows[cell.Y][cell.X] = unicode.ToUpper(ownerLabel(i))
+	}
+	text := make([]string, len(rows))
+	for y, row := range rows {
+		text[y] = string(row)
+	}
+	return text
+}
+
+func ownerLabel(owner int) rune {
+	if owner == tie {
+		return '.'
+	}
+	return 'a' + rune(owner%26)
+}
@@ -0,0 +1,154 @@
+package day07
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// A CriticalPath is the chain of dependent steps that takes the longest to
+// build. No number of workers can build all of the steps any faster.
+type CriticalPath struct {
+	Steps []string `json:"steps"`
+	Time  int      `json:"time"`
+}
+
+// FindCriticalPath returns the critical path through the steps, given how long
+// each of them takes. If several paths take as long, the path chosen ends with
+// whichever of their last steps comes first in the build order, and each step
+// on it waits on whichever of its slowest dependencies comes first.
+func FindCriticalPath(input string, config SchedulerConfig) (CriticalPath, error) {
+	reqs, err := parseInput(input)
+	if err != nil {
+		return CriticalPath{}, err
+	}
+	graph := newDependencyGraph(reqs, false)
+	durations, err := config.stepDurations(graph.steps)
+	if err != nil {
+		return CriticalPath{}, err
+	}
+	return graph.criticalPath(durations)
+}
+
+// criticalPath visits the steps in topological order, working out when each
+// one could start at the earliest with unlimited workers, and which of its
+// dependencies it would be waiting on last. Like building the steps, it uses
+// up the graph.
+func (graph *dependencyGraph) criticalPath(durations map[string]int) (CriticalPath, error) {
+	starts := make(map[string]int, len(graph.steps))
+	waitingOn := make(map[string]string, len(graph.steps))
+	path := CriticalPath{Steps: []string{}}
+	last := ""
+	for range graph.steps {
+		step, stepAvailable := graph.getNextStep()
+		if !stepAvailable {
+			return path, fmt.Errorf("cycle detected")
+		}
+		end := starts[step] + durations[step]
+		if end > path.Time {
+			last, path.Time = step, end
+		}
+		for _, dependent := range graph.adjList[step] {
+			if end > starts[dependent] {
+				starts[dependent] = end
+				waitingOn[dependent] = step
+			}
+		}
+		graph.stepWasBuilt(step)
+	}
+	for step := last; step != ""; step = waitingOn[step] {
+		path.Steps = append([]string{step}, path.Steps...)
+	}
+	return path, nil
+}
+
+// MinWorkers returns the fewest workers that Schedule needs to build the
+// steps as quickly as the critical path allows, ignoring config.Workers. It
+// never needs more workers than steps, since then every step is started as
+// soon as it's available.
+func MinWorkers(input string, config SchedulerConfig) (int, error) {
+	path, err := FindCriticalPath(input, config)
+	if err != nil {
+		return 0, err
+	}
+	for config.Workers = 1; ; config.Workers++ {
+		timeline, err := Schedule(input, config)
+		if err != nil {
+			return 0, err
+		}
+		if timeline.Time == path.Time {
+			return config.Workers, nil
+		}
+		if config.Workers >= len(timeline.Tasks) {
+			return 0, fmt.Errorf("%d workers took %d, but the critical path takes %d",
+				config.Workers, timeline.Time, path.Time)
+		}
+	}
+}
+
+// WriteDOT writes the dependency graph in Graphviz's DOT language, with each
+// step labelled with its duration and the critical path highlighted.
+func WriteDOT(w io.Writer, input string, config SchedulerConfig) error {
+	reqs, err := parseInput(input)
+	if err != nil {
+		return err
+	}
+	graph := newDependencyGraph(reqs, false)
+	durations, err := config.stepDurations(graph.steps)
+	if err != nil {
+		return err
+	}
+	// Make a copy first, since finding the critical path uses up the graph.
+	adjList := make(map[string][]string, len(graph.adjList))
+	for step, dependents := range graph.adjList {
+		adjList[step] = append([]string{}, dependents...)
+		sort.Strings(adjList[step])
+	}
+	path, err := graph.criticalPath(durations)
+	if err != nil {
+		return err
+	}
+	critical := make(map[string]bool)
+	criticalEdges := make(map[[2]string]bool)
+	for i, step := range path.Steps {
+		critical[step] = true
+		if i > 0 {
+			criticalEdges[[2]string{path.Steps[i-1], step}] = true
+		}
+	}
+	const highlight = "color=red, penwidth=2"
+
+	for _, line := range []string{"digraph steps {", "  rankdir=LR;", "  node [shape=box];"} {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	for _, step := range graph.steps {
+		style := ""
+		if critical[step] {
+			style = ", " + highlight
+		}
+		if _, err := fmt.Fprintf(w, "  %s [label=%s%s];\n", strconv.Quote(step),
+			strconv.Quote(fmt.Sprintf("%s (%d)", step, durations[step])), style); err != nil {
+			return err
+		}
+	}
+	for _, step := range graph.steps {
+		for i, dependent := range adjList[step] {
+			if i > 0 && dependent == adjList[step][i-1] {
+				// The requirement was given more than once.
+				continue
+			}
+			style := ""
+			if criticalEdges[[2]string{step, dependent}] {
+				style = " [" + highlight + "]"
+			}
+			if _, err := fmt.Fprintf(w, "  %s -> %s%s;\n", strconv.Quote(step), strconv.Quote(dependent), style); err != nil {
+				return err
+			}
+		}
+	}
+	_, err = fmt.Fprintln(w, "}")
+	return err
+}
@@ -4,14 +4,15 @@ import (
 	"container/heap"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/orn688/advent-of-code-2018/internal/util"
 )
 
-var lineRegex = regexp.MustCompile(`^Step (?P<Dependency>[A-Z]) must be ` +
-	`finished before step (?P<Depender>[A-Z]) can begin.$`)
+var lineRegex = regexp.MustCompile(`^Step (?P<Dependency>\S+) must be ` +
+	`finished before step (?P<Depender>\S+) can begin.$`)
 
 // A Requirement is a 2-tuple made up of a step name (the Depender) and the
 // name of a step that it depends on (the Dependency).
@@ -21,17 +22,56 @@ type requirement struct {
 	Dependency string
 }
 
-type stepInProgress struct {
-	Step     string
-	TimeLeft int
+// A SchedulerConfig describes the workers building the steps and how long
+// each step takes.
+type SchedulerConfig struct {
+	Workers int
+	// BaseDuration is the time taken by every step on top of its letter's
+	// position in the alphabet, so step A takes BaseDuration+1.
+	BaseDuration int
+	// Durations overrides the time taken by individual steps.
+	Durations map[string]int
+	// Reverse builds the available steps in reverse alphabetical order
+	// instead of alphabetical order.
+	Reverse bool
 }
 
+// stepDurations returns the time taken to build each of the steps. Steps named
+// with a single letter from A to Z take BaseDuration plus the letter's
+// position in the alphabet, and any other steps need their durations to be
+// overridden. Every step must take at least 1 second.
+func (config SchedulerConfig) stepDurations(steps []string) (map[string]int, error) {
+	if config.BaseDuration < 0 {
+		return nil, fmt.Errorf("invalid base duration: %d", config.BaseDuration)
+	}
+	durations := make(map[string]int, len(steps))
+	for _, step := range steps {
+		if duration, ok := config.Durations[step]; ok {
+			durations[step] = duration
+		} else if len(step) == 1 && 'A' <= step[0] && step[0] <= 'Z' {
+			durations[step] = config.BaseDuration + int(step[0]-'A') + 1
+		} else {
+			return durations, fmt.Errorf("no duration given for step %q", step)
+		}
+		if durations[step] < 1 {
+			return durations, fmt.Errorf("invalid duration for step %q: %d", step, durations[step])
+		}
+	}
+	return durations, nil
+}
+
+// DefaultSchedulerConfig is the configuration from the puzzle.
+var DefaultSchedulerConfig = SchedulerConfig{Workers: 5, BaseDuration: 60}
+
 // A dependencyGraph is an adjacency list mapping step names to the names of
 // steps that depend on them.
 // reverseAlphaOrder indicates whether a step named B should be built before a
 // step named A, all else being equal
 type dependencyGraph struct {
-	adjList                 map[string][]string
+	adjList map[string][]string
+	// steps holds the step names in alphabetical order.
+	steps                   []string
+	ranks                   map[string]int
 	stepsReadyToBuild       util.StringHeap
 	unbuiltDependencyCounts map[string]int
 	reverseAlphaOrder       bool
@@ -52,11 +92,25 @@ func newDependencyGraph(reqs []requirement, reverse bool) dependencyGraph {
 			graph.adjList[req.Depender] = make([]string, 0)
 		}
 	}
+	graph.setRanks()
 	graph.setDependencyCounts()
 	graph.setStepsReadyToBuild()
 	return graph
 }
 
+// Rank the steps alphabetically, which orders them in the heap.
+func (graph *dependencyGraph) setRanks() {
+	graph.steps = make([]string, 0, len(graph.adjList))
+	for step := range graph.adjList {
+		graph.steps = append(graph.steps, step)
+	}
+	sort.Strings(graph.steps)
+	graph.ranks = make(map[string]int, len(graph.steps))
+	for rank, step := range graph.steps {
+		graph.ranks[step] = rank
+	}
+}
+
 // Set the number of dependencies for each build step.
 func (graph *dependencyGraph) setDependencyCounts() {
 	for step := range graph.adjList {
@@ -80,13 +134,13 @@ func (graph *dependencyGraph) setStepsReadyToBuild() {
 }
 
 func (graph *dependencyGraph) markAsReadyToBuild(step string) {
-	priority := int(step[0])
+	priority := graph.ranks[step]
 	if graph.reverseAlphaOrder {
 		priority *= -1
 	}
 	heap.Push(&graph.stepsReadyToBuild, util.HeapElement{
 		Value:    step,
-		Priority: int(step[0]),
+		Priority: priority,
 	})
 }
 
@@ -112,11 +166,18 @@ func (graph *dependencyGraph) getNextStep() (string, bool) {
 // Part1 returns a topological ordering of the steps, based on the requirements
 // in the input.
 func Part1(input string) (string, error) {
+	return BuildOrder(input, false)
+}
+
+// BuildOrder returns the order in which a single worker builds the steps,
+// choosing between the available steps alphabetically, or in reverse
+// alphabetical order if reverse is set.
+func BuildOrder(input string, reverse bool) (string, error) {
 	reqs, err := parseInput(input)
 	if err != nil {
 		return "", err
 	}
-	graph := newDependencyGraph(reqs, false)
+	graph := newDependencyGraph(reqs, reverse)
 	ordering := make([]string, len(graph.adjList))
 
 	for i := 0; i < len(ordering); i++ {
@@ -131,63 +192,105 @@ func Part1(input string) (string, error) {
 		graph.stepWasBuilt(nextStep)
 	}
 
-	return strings.Join(ordering, ""), nil
+	return joinSteps(ordering), nil
 }
 
-// Part2 returns the time it would take workerCount workers to complete the
-// steps.
+// joinSteps runs the step names together like the puzzle does, unless any of
+// them is longer than a character, in which case they're separated by commas.
+func joinSteps(steps []string) string {
+	for _, step := range steps {
+		if len(step) > 1 {
+			return strings.Join(steps, ",")
+		}
+	}
+	return strings.Join(steps, "")
+}
+
+// Part2 returns the time it would take 5 workers to complete the steps, if
+// each step takes 60 seconds plus its position in the alphabet.
 func Part2(input string) (string, error) {
-	time, err := timeToComplete(input, 5, 61)
+	time, err := TimeToComplete(input, DefaultSchedulerConfig)
 	if err != nil {
 		return "", err
 	}
 	return strconv.Itoa(time), nil
 }
 
-func timeToComplete(input string, workerCount int, durationOfStepA int) (int, error) {
+// TimeToComplete returns the time it takes to build all of the steps.
+func TimeToComplete(input string, config SchedulerConfig) (int, error) {
+	timeline, err := Schedule(input, config)
+	return timeline.Time, err
+}
+
+// Schedule returns what each worker does while building the steps. Whenever
+// workers are idle, they take the available steps in order of priority, with
+// the first worker taking the first step.
+func Schedule(input string, config SchedulerConfig) (Timeline, error) {
+	timeline := Timeline{Workers: config.Workers, Tasks: []Task{}}
+	if config.Workers < 1 {
+		return timeline, fmt.Errorf("need at least 1 worker, got %d", config.Workers)
+	}
 	reqs, err := parseInput(input)
 	if err != nil {
-		return 0, err
-	}
-
-	graph := newDependencyGraph(reqs, true)
-	currentSteps := make([]stepInProgress, workerCount)
-	complete := false
-	time := -1
-	for !complete {
-		time++
-		// Assume, then verify
-		complete = true
-		for i := range currentSteps {
-			if currentSteps[i].TimeLeft == 0 {
-				oldStep := currentSteps[i].Step
-				if oldStep != "" {
-					graph.stepWasBuilt(oldStep)
-				}
+		return timeline, err
+	}
+
+	graph := newDependencyGraph(reqs, config.Reverse)
+	durations, err := config.stepDurations(graph.steps)
+	if err != nil {
+		return timeline, err
+	}
+	// The index into timeline.Tasks of each worker's current task, or -1.
+	currentTasks := make([]int, config.Workers)
+	for i := range currentTasks {
+		currentTasks[i] = -1
+	}
+	built := 0
+	time := 0
+	for {
+		// Finish every step that ends now before handing out new ones, so
+		// that all of the steps they unblock are up for grabs.
+		for worker, current := range currentTasks {
+			if current >= 0 && timeline.Tasks[current].End == time {
+				graph.stepWasBuilt(timeline.Tasks[current].Step)
+				built++
+				currentTasks[worker] = -1
+			}
+		}
+		busy := false
+		for worker := range currentTasks {
+			if currentTasks[worker] < 0 {
 				nextStep, stepAvailable := graph.getNextStep()
-				if stepAvailable {
-					complete = false
-					currentSteps[i] = stepInProgress{
-						Step:     nextStep,
-						TimeLeft: stepDuration(nextStep, durationOfStepA) - 1,
-					}
-				} else {
-					currentSteps[i].Step = ""
+				if !stepAvailable {
+					continue
 				}
-			} else {
-				currentSteps[i].TimeLeft--
-				complete = false
+				currentTasks[worker] = len(timeline.Tasks)
+				timeline.Tasks = append(timeline.Tasks, Task{
+					Worker: worker,
+					Step:   nextStep,
+					Start:  time,
+					End:    time + durations[nextStep],
+				})
 			}
+			busy = true
 		}
-	}
-
-	for _, dependencyCount := range graph.unbuiltDependencyCounts {
-		if dependencyCount > 0 {
-			return 0, fmt.Errorf("cycle detected")
+		if !busy {
+			break
+		}
+		// Skip ahead to the next time a step ends.
+		time = -1
+		for _, current := range currentTasks {
+			if current >= 0 && (time < 0 || timeline.Tasks[current].End < time) {
+				time = timeline.Tasks[current].End
+			}
 		}
 	}
 
-	return time, nil
+	if built < len(graph.adjList) {
+		return timeline, fmt.Errorf("cycle detected")
+	}
+	timeline.Time = time
+	return timeline, nil
 }
 
 func parseInput(input string) ([]requirement, error) {
@@ -203,8 +306,7 @@ func parseInput(input string) ([]requirement, error) {
 	return reqs, nil
 }
 
-// Convert a line to a requirement, assuming each step name is a length-1
-// string of capital ASCII letters.
+// Convert a line to a requirement. Step names can be anything without spaces.
 func parseLine(line string) (req requirement, err error) {
 	groups, err := util.CaptureRegexGroups(lineRegex, line)
 	if err != nil {
@@ -217,7 +319,20 @@ func parseLine(line string) (req requirement, err error) {
 	return
 }
 
-// Assumes the step has length 1 and is A-Z.
-func stepDuration(step string, durationOfStepA int) int {
-	return durationOfStepA + (int(step[0]) - int('A'))
+// ParseDurations parses per-step durations given as NAME=SECONDS, e.g. "A=5".
+// Durations must be positive.
+func ParseDurations(overrides []string) (map[string]int, error) {
+	durations := make(map[string]int, len(overrides))
+	for _, override := range overrides {
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 {
+			return durations, fmt.Errorf("invalid duration %q, expected NAME=SECONDS", override)
+		}
+		duration, err := strconv.Atoi(parts[1])
+		if err != nil || duration < 1 {
+			return durations, fmt.Errorf("invalid duration %q, expected a positive number of seconds", override)
+		}
+		durations[parts[0]] = duration
+	}
+	return durations, nil
 }
@@ -1,7 +1,13 @@
 package day07
 
 import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/orn688/advent-of-code-2018/internal/testutil"
 )
 
 const input = `
@@ -15,8 +21,17 @@ Step F must be finished before step E can begin.
 `
 
 func TestPart1(t *testing.T) {
-	expected := "CABDFE"
-	actual, _ := Part1(input)
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Name: "example", Input: input, Expected: "CABDFE"},
+	})
+}
+
+func TestBuildOrderReverse(t *testing.T) {
+	expected := "CFADBE"
+	actual, err := BuildOrder(input, true)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if actual != expected {
 		t.Errorf("expected %s, actual %s", expected, actual)
 	}
@@ -24,8 +39,267 @@ func TestPart1(t *testing.T) {
 
 func TestPart2(t *testing.T) {
 	expected := 15
-	actual, _ := timeToComplete(input, 2, 1)
+	actual, _ := TimeToComplete(input, SchedulerConfig{Workers: 2})
+	if actual != expected {
+		t.Errorf("expected %d, actual %d", expected, actual)
+	}
+}
+
+// slowRoot has three independent steps, the last of which takes much longer
+// than the others.
+const slowRoot = `
+Step A must be finished before step Y can begin.
+Step B must be finished before step Y can begin.
+Step Z must be finished before step Y can begin.
+`
+
+func TestTimeToComplete(t *testing.T) {
+	testcases := []struct {
+		name     string
+		input    string
+		config   SchedulerConfig
+		expected int
+	}{
+		{
+			// A single worker takes as long as all of the steps put together.
+			name:     "one worker",
+			input:    input,
+			config:   SchedulerConfig{Workers: 1, BaseDuration: 10},
+			expected: 6*10 + 1 + 2 + 3 + 4 + 5 + 6,
+		},
+		{
+			// Taking A and B first leaves the slow Z until last.
+			name:     "alphabetical",
+			input:    slowRoot,
+			config:   SchedulerConfig{Workers: 2},
+			expected: 1 + 26 + 25,
+		},
+		{
+			// Taking Z first lets the other worker get through A and B in
+			// the meantime.
+			name:     "reverse",
+			input:    slowRoot,
+			config:   SchedulerConfig{Workers: 2, Reverse: true},
+			expected: 26 + 25,
+		},
+		{
+			// When B finishes, C and D are both up for grabs, including by
+			// the first worker, which has been idle since finishing A.
+			name: "freed steps",
+			input: `
+Step A must be finished before step E can begin.
+Step B must be finished before step C can begin.
+Step B must be finished before step D can begin.
+Step C must be finished before step E can begin.
+Step D must be finished before step E can begin.
+`,
+			config:   SchedulerConfig{Workers: 2},
+			expected: 2 + 4 + 5,
+		},
+	}
+	for _, tc := range testcases {
+		actual, err := TimeToComplete(tc.input, tc.config)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		} else if actual != tc.expected {
+			t.Errorf("%s: expected %d, actual %d", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestTimeToCompleteCycle(t *testing.T) {
+	cycle := `
+Step A must be finished before step B can begin.
+Step B must be finished before step C can begin.
+Step C must be finished before step B can begin.
+`
+	if _, err := TimeToComplete(cycle, SchedulerConfig{Workers: 2}); err == nil {
+		t.Error("expected an error for a cycle")
+	}
+	if _, err := TimeToComplete(input, SchedulerConfig{}); err == nil {
+		t.Error("expected an error for no workers")
+	}
+}
+
+func TestInvalidDurations(t *testing.T) {
+	for _, config := range []SchedulerConfig{
+		{Workers: 2, BaseDuration: -5},
+		{Workers: 2, Durations: map[string]int{"C": 0}},
+	} {
+		if _, err := TimeToComplete(input, config); err == nil {
+			t.Errorf("%+v: expected an error", config)
+		}
+		if _, err := MinWorkers(input, config); err == nil {
+			t.Errorf("%+v: expected an error", config)
+		}
+	}
+}
+
+func TestParseDurations(t *testing.T) {
+	expected := map[string]int{"A": 5, "Q": 100}
+	actual, err := ParseDurations([]string{"A=5", "Q=100"})
+	if err != nil || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, actual %v (%v)", expected, actual, err)
+	}
+	for _, override := range []string{"A", "A=0", "A=x"} {
+		if _, err := ParseDurations([]string{override}); err == nil {
+			t.Errorf("%s: expected an error", override)
+		}
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	timeline, err := Schedule(input, SchedulerConfig{Workers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var table strings.Builder
+	if err := timeline.WriteTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	// The example from the puzzle.
+	testutil.AssertGridEqual(t, `
+Second   Worker 1   Worker 2   Done
+   0        C          .
+   1        C          .
+   2        C          .
+   3        A          F       C
+   4        B          F       CA
+   5        B          F       CA
+   6        D          F       CAB
+   7        D          F       CAB
+   8        D          F       CAB
+   9        D          .       CABF
+  10        E          .       CABFD
+  11        E          .       CABFD
+  12        E          .       CABFD
+  13        E          .       CABFD
+  14        E          .       CABFD
+  15        .          .       CABFDE
+`, table.String())
+	testutil.AssertWriteErrors(t, timeline.WriteTable)
+}
+
+func TestWriteSVG(t *testing.T) {
+	timeline, err := Schedule(input, SchedulerConfig{Workers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var svg bytes.Buffer
+	if err := timeline.WriteSVG(&svg); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertGolden(t, "example.svg", svg.Bytes())
+	testutil.AssertWriteErrors(t, timeline.WriteSVG)
+}
+
+func TestFindCriticalPath(t *testing.T) {
+	expected := CriticalPath{Steps: []string{"C", "F", "E"}, Time: 3 + 6 + 5}
+	actual, err := FindCriticalPath(input, SchedulerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+
+	// Slowing down D makes the other branch critical.
+	expected = CriticalPath{Steps: []string{"C", "A", "D", "E"}, Time: 3 + 1 + 10 + 5}
+	actual, _ = FindCriticalPath(input, SchedulerConfig{Durations: map[string]int{"D": 10}})
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+
+	// When both branches take as long, D is built before F, so E waits on it.
+	expected = CriticalPath{Steps: []string{"C", "A", "D", "E"}, Time: 3 + 1 + 4 + 5}
+	actual, _ = FindCriticalPath(input, SchedulerConfig{Durations: map[string]int{"D": 4, "F": 5}})
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+}
+
+func TestMinWorkers(t *testing.T) {
+	// Two workers take 15 seconds, but A's branch needs a third worker to
+	// keep up with C, F and E.
+	expected := 3
+	actual, err := MinWorkers(input, SchedulerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	if actual != expected {
 		t.Errorf("expected %d, actual %d", expected, actual)
 	}
+	if time, _ := TimeToComplete(input, SchedulerConfig{Workers: actual}); time != 14 {
+		t.Errorf("expected %d, actual %d", 14, time)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var dot bytes.Buffer
+	if err := WriteDOT(&dot, input, SchedulerConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertGolden(t, "example.dot", dot.Bytes())
+	testutil.AssertWriteErrors(t, func(w io.Writer) error {
+		return WriteDOT(w, input, SchedulerConfig{})
+	})
+}
+
+func TestLongStepNames(t *testing.T) {
+	const recipe = `
+Step fetch must be finished before step build can begin.
+Step configure must be finished before step build can begin.
+Step build must be finished before step test can begin.
+Step build must be finished before step package can begin.
+`
+	order, err := BuildOrder(recipe, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "configure,fetch,build,package,test"; order != expected {
+		t.Errorf("expected %s, actual %s", expected, order)
+	}
+
+	config := SchedulerConfig{
+		Workers:   2,
+		Durations: map[string]int{"fetch": 4, "configure": 1, "build": 10, "test": 3, "package": 2},
+	}
+	path, err := FindCriticalPath(recipe, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedPath := CriticalPath{Steps: []string{"fetch", "build", "test"}, Time: 4 + 10 + 3}
+	if !reflect.DeepEqual(path, expectedPath) {
+		t.Errorf("expected %v, actual %v", expectedPath, path)
+	}
+	if time, _ := TimeToComplete(recipe, config); time != path.Time {
+		t.Errorf("expected %d, actual %d", path.Time, time)
+	}
+
+	delete(config.Durations, "test")
+	if _, err := TimeToComplete(recipe, config); err == nil {
+		t.Error("expected an error for a step without a duration")
+	}
+}
+
+func TestWriteTableNonASCIINames(t *testing.T) {
+	const recipe = "Step café must be finished before step crème-brûlée can begin.\n"
+	timeline, err := Schedule(recipe, SchedulerConfig{
+		Workers:   1,
+		Durations: map[string]int{"café": 1, "crème-brûlée": 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var table strings.Builder
+	if err := timeline.WriteTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertGridEqual(t, `
+Second   Worker 1       Done
+   0         café
+   1     crème-brûlée   café
+   2     crème-brûlée   café
+   3          .         café,crème-brûlée
+`, table.String())
 }
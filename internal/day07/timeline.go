@@ -0,0 +1,144 @@
+package day07
+
+import (
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// A Task is a single step built by a worker, from Start up to End.
+type Task struct {
+	// Worker is the index of the worker, starting at 0.
+	Worker int    `json:"worker"`
+	Step   string `json:"step"`
+	Start  int    `json:"start"`
+	End    int    `json:"end"`
+}
+
+// A Timeline records every task, in the order that they were started.
+type Timeline struct {
+	Workers int    `json:"workers"`
+	Tasks   []Task `json:"tasks"`
+	// Time is when the last step is finished.
+	Time int `json:"time"`
+}
+
+// The size of a second and of a worker's row in SVGs.
+const (
+	svgSecondWidth = 10
+	svgRowHeight   = 24
+	svgLabelWidth  = 80
+)
+
+var (
+	svgTaskColor  = color.RGBA{0x9e, 0xc5, 0xe8, 0xff}
+	svgLabelColor = color.RGBA{0x33, 0x33, 0x33, 0xff}
+)
+
+// stepAt returns the step that the worker is building during the given
+// second, or "" if it's idle.
+func (timeline Timeline) stepAt(worker, second int) string {
+	for _, task := range timeline.Tasks {
+		if task.Worker == worker && task.Start <= second && second < task.End {
+			return task.Step
+		}
+	}
+	return ""
+}
+
+// finished returns the tasks in the order that they were finished.
+func (timeline Timeline) finished() []Task {
+	tasks := append([]Task{}, timeline.Tasks...)
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].End != tasks[j].End {
+			return tasks[i].End < tasks[j].End
+		}
+		return tasks[i].Worker < tasks[j].Worker
+	})
+	return tasks
+}
+
+// WriteTable writes the timeline like the puzzle does, with a row for every
+// second showing what each worker is doing and which steps are done.
+func (timeline Timeline) WriteTable(w io.Writer) error {
+	// Widths are counted in runes, like fmt's padding, so that non-ASCII step
+	// names still line up.
+	width := len("Worker 1")
+	for _, task := range timeline.Tasks {
+		if length := utf8.RuneCountInString(task.Step); length > width {
+			width = length
+		}
+	}
+	header := []string{"Second"}
+	for worker := 0; worker < timeline.Workers; worker++ {
+		header = append(header, fmt.Sprintf("%-*s", width, fmt.Sprintf("Worker %d", worker+1)))
+	}
+	header = append(header, "Done")
+	if _, err := fmt.Fprintln(w, strings.Join(header, "   ")); err != nil {
+		return err
+	}
+
+	finished := timeline.finished()
+	done := []string{}
+	for second := 0; second <= timeline.Time; second++ {
+		for len(finished) > 0 && finished[0].End <= second {
+			done = append(done, finished[0].Step)
+			finished = finished[1:]
+		}
+		row := []string{fmt.Sprintf("%-6s", fmt.Sprintf("%4d", second))}
+		for worker := 0; worker < timeline.Workers; worker++ {
+			step := timeline.stepAt(worker, second)
+			if step == "" {
+				step = "."
+			}
+			// Center the step under the worker's heading.
+			padding := (width - utf8.RuneCountInString(step)) / 2
+			row = append(row, fmt.Sprintf("%-*s", width, strings.Repeat(" ", padding)+step))
+		}
+		row = append(row, joinSteps(done))
+		if _, err := fmt.Fprintln(w, strings.TrimRight(strings.Join(row, "   "), " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteSVG draws the timeline as a Gantt chart, with a row for each worker
+// and a bar for each task. Hovering over a bar shows when the task started and
+// ended.
+func (timeline Timeline) WriteSVG(w io.Writer) error {
+	width := svgLabelWidth + timeline.Time*svgSecondWidth
+	height := timeline.Workers * svgRowHeight
+	if _, err := fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 %d %d\" font-family=\"sans-serif\" font-size=\"12\">\n",
+		width, height); err != nil {
+		return err
+	}
+	for worker := 0; worker < timeline.Workers; worker++ {
+		if _, err := fmt.Fprintf(w, "  <text x=\"4\" y=\"%d\" fill=\"%s\">Worker %d</text>\n",
+			worker*svgRowHeight+svgRowHeight*2/3, hexColor(svgLabelColor), worker+1); err != nil {
+			return err
+		}
+	}
+	for _, task := range timeline.Tasks {
+		x, y := svgLabelWidth+task.Start*svgSecondWidth, task.Worker*svgRowHeight
+		barWidth := (task.End - task.Start) * svgSecondWidth
+		if _, err := fmt.Fprintf(w, "  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\" stroke=\"white\"><title>%s: %d to %d</title></rect>\n",
+			x, y+2, barWidth, svgRowHeight-4, hexColor(svgTaskColor), html.EscapeString(task.Step), task.Start, task.End); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "  <text x=\"%d\" y=\"%d\" text-anchor=\"middle\" fill=\"%s\">%s</text>\n",
+			x+barWidth/2, y+svgRowHeight*2/3, hexColor(svgLabelColor), html.EscapeString(task.Step)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "</svg>")
+	return err
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -1,30 +1,21 @@
 package day08
 
 import (
-	"strconv"
 	"testing"
+
+	"github.com/orn688/advent-of-code-2018/internal/testutil"
 )
 
+const input = "2 3 0 3 10 11 12 1 1 0 1 99 2 1 1 2"
+
 func TestPart1(t *testing.T) {
-	testcases := map[string]int{
-		"2 3 0 3 10 11 12 1 1 0 1 99 2 1 1 2": 138,
-	}
-	for input, expected := range testcases {
-		actual, _ := Part1(input)
-		if actual != strconv.Itoa(expected) {
-			t.Errorf("expected %d, actual %s", expected, actual)
-		}
-	}
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Input: input, Expected: "138"},
+	})
 }
 
 func TestPart2(t *testing.T) {
-	testcases := map[string]int{
-		"2 3 0 3 10 11 12 1 1 0 1 99 2 1 1 2": 66,
-	}
-	for input, expected := range testcases {
-		actual, _ := Part2(input)
-		if actual != strconv.Itoa(expected) {
-			t.Errorf("expected %d, actual %s", expected, actual)
-		}
-	}
+	testutil.RunExamples(t, Part2, []testutil.Example{
+		{Input: input, Expected: "66"},
+	})
 }
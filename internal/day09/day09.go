@@ -1,7 +1,6 @@
 package day09
 
 import (
-	"container/list"
 	"regexp"
 	"strconv"
 
@@ -34,31 +33,20 @@ func Part2(input string) (string, error) {
 }
 
 func playGame(playerCount, lastMarble int) []int {
-	marbles := list.New()
+	// The current marble is always kept at the back of the circle, with
+	// clockwise running from front to back.
+	marbles := util.NewIntDeque(lastMarble + 1)
+	marbles.PushBack(0)
 	scores := make([]int, playerCount)
-	var currentMarble *list.Element
-	for marble := 0; marble <= lastMarble; marble++ {
-		if currentMarble == nil {
-			currentMarble = marbles.PushBack(marble)
-		} else if marble%23 == 0 {
+	for marble := 1; marble <= lastMarble; marble++ {
+		if marble%23 == 0 {
 			player := marble % playerCount
-			scores[player] += marble
-			var nextCurrentMarble *list.Element
-			for i := 0; i < 7; i++ {
-				nextCurrentMarble = currentMarble
-				currentMarble = currentMarble.Prev()
-				if currentMarble == nil {
-					currentMarble = marbles.Back()
-				}
-			}
-			scores[player] += marbles.Remove(currentMarble).(int)
-			currentMarble = nextCurrentMarble
+			marbles.Rotate(7)
+			scores[player] += marble + marbles.PopBack()
+			marbles.Rotate(-1)
 		} else {
-			currentMarble = currentMarble.Next()
-			if currentMarble == nil {
-				currentMarble = marbles.Front()
-			}
-			currentMarble = marbles.InsertAfter(marble, currentMarble)
+			marbles.Rotate(-1)
+			marbles.PushBack(marble)
 		}
 	}
 	return scores
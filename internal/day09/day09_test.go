@@ -1,22 +1,77 @@
 package day09
 
 import (
-	"strconv"
+	"container/list"
+	"reflect"
 	"testing"
+
+	"github.com/orn688/advent-of-code-2018/internal/testutil"
 )
 
 func TestPart1(t *testing.T) {
-	testcases := map[string]int{
-		"10 players; last marble is worth 1618 points": 8317,
-		"13 players; last marble is worth 7999 points": 146373,
-		"17 players; last marble is worth 1104 points": 2764,
-		"21 players; last marble is worth 6111 points": 54718,
-		"30 players; last marble is worth 5807 points": 37305,
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Input: "10 players; last marble is worth 1618 points", Expected: "8317"},
+		{Input: "13 players; last marble is worth 7999 points", Expected: "146373"},
+		{Input: "17 players; last marble is worth 1104 points", Expected: "2764"},
+		{Input: "21 players; last marble is worth 6111 points", Expected: "54718"},
+		{Input: "30 players; last marble is worth 5807 points", Expected: "37305"},
+	})
+}
+
+func TestPlayGameMatchesList(t *testing.T) {
+	for playerCount := 1; playerCount <= 30; playerCount += 7 {
+		for _, lastMarble := range []int{0, 1, 22, 23, 24, 1618} {
+			expected := playGameList(playerCount, lastMarble)
+			actual := playGame(playerCount, lastMarble)
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("%d players, last marble %d: expected %v, actual %v",
+					playerCount, lastMarble, expected, actual)
+			}
+		}
+	}
+}
+
+func BenchmarkPlayGame(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		playGame(430, 7158800)
+	}
+}
+
+func BenchmarkPlayGameList(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		playGameList(430, 7158800)
 	}
-	for input, expected := range testcases {
-		actual, _ := Part1(input)
-		if actual != strconv.Itoa(expected) {
-			t.Errorf("expected %d, actual %s", expected, actual)
+}
+
+// playGameList is the original container/list implementation of playGame,
+// kept as a reference.
+func playGameList(playerCount, lastMarble int) []int {
+	marbles := list.New()
+	scores := make([]int, playerCount)
+	var currentMarble *list.Element
+	for marble := 0; marble <= lastMarble; marble++ {
+		if currentMarble == nil {
+			currentMarble = marbles.PushBack(marble)
+		} else if marble%23 == 0 {
+			player := marble % playerCount
+			scores[player] += marble
+			var nextCurrentMarble *list.Element
+			for i := 0; i < 7; i++ {
+				nextCurrentMarble = currentMarble
+				currentMarble = currentMarble.Prev()
+				if currentMarble == nil {
+					currentMarble = marbles.Back()
+				}
+			}
+			scores[player] += marbles.Remove(currentMarble).(int)
+			currentMarble = nextCurrentMarble
+		} else {
+			currentMarble = currentMarble.Next()
+			if currentMarble == nil {
+				currentMarble = marbles.Front()
+			}
+			currentMarble = marbles.InsertAfter(marble, currentMarble)
 		}
 	}
+	return scores
 }
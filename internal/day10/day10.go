@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/orn688/advent-of-code-2018/internal/geom"
 	"github.com/orn688/advent-of-code-2018/internal/util"
 )
 
@@ -12,29 +13,25 @@ var lineRegex = regexp.MustCompile(`^position=<( ?)(?P<X>-?\d+), ( ?)(?P<Y>-?\d+
 	`velocity=<( ?)(?P<Vx>-?\d+), ( ?)(?P<Vy>-?\d+)>$`)
 
 type point struct {
-	X  int
-	Y  int
-	vx int
-	vy int
+	geom.Point2
+	velocity geom.Point2
 }
 
 func (p *point) step() {
-	p.X += p.vx
-	p.Y += p.vy
+	p.Point2 = p.Add(p.velocity)
 }
 
 func (p *point) stepBack() {
-	p.X -= p.vx
-	p.Y -= p.vy
+	p.Point2 = p.Sub(p.velocity)
 }
 
 type pointgrid struct {
-	points                 []*point
-	minX, maxX, minY, maxY int
+	points []*point
+	bounds geom.Rect
 }
 
 func newGrid(points []*point) *pointgrid {
-	grid := &pointgrid{points, 0, 0, 0, 0}
+	grid := &pointgrid{points, geom.Rect{}}
 	grid._refreshBounds()
 	return grid
 }
@@ -62,7 +59,7 @@ func (grid *pointgrid) asString() string {
 		arr[y] = make([]bool, grid.width())
 	}
 	for _, pt := range grid.points {
-		arr[pt.Y-grid.minY][pt.X-grid.minX] = true
+		arr[pt.Y-grid.bounds.Min.Y][pt.X-grid.bounds.Min.X] = true
 	}
 
 	outputRows := make([]string, grid.height())
@@ -95,32 +92,23 @@ func (grid *pointgrid) stepBack() {
 }
 
 func (grid *pointgrid) width() int {
-	return grid.maxX - grid.minX + 1
+	return grid.bounds.Width()
 }
 
 func (grid *pointgrid) height() int {
-	return grid.maxY - grid.minY + 1
+	return grid.bounds.Height()
 }
 
 func (grid *pointgrid) bboxArea() int {
-	return grid.width() * grid.height()
+	return grid.bounds.Area()
 }
 
 func (grid *pointgrid) _refreshBounds() {
-	grid.minX, grid.maxX = grid.points[0].X, grid.points[0].X
-	grid.minY, grid.maxY = grid.points[0].Y, grid.points[0].Y
-	for _, pt := range grid.points {
-		if pt.X > grid.maxX {
-			grid.maxX = pt.X
-		} else if pt.X < grid.minX {
-			grid.minX = pt.X
-		}
-		if pt.Y > grid.maxY {
-			grid.maxY = pt.Y
-		} else if pt.Y < grid.minY {
-			grid.minY = pt.Y
-		}
+	positions := make([]geom.Point2, len(grid.points))
+	for i, pt := range grid.points {
+		positions[i] = pt.Point2
 	}
+	grid.bounds = geom.BoundingBox(positions)
 }
 
 // Part1 returns the message that the points form at the moment that they
@@ -176,10 +164,8 @@ func parseLine(line string) (pt *point, err error) {
 		return
 	}
 	pt = &point{
-		X:  x,
-		Y:  y,
-		vx: vx,
-		vy: vy,
+		Point2:   geom.Point2{X: x, Y: y},
+		velocity: geom.Point2{X: vx, Y: vy},
 	}
 	return
 }
@@ -1,8 +1,9 @@
 package day10
 
 import (
-	"strings"
 	"testing"
+
+	"github.com/orn688/advent-of-code-2018/internal/testutil"
 )
 
 const testInput = `position=< 9,  1> velocity=< 0,  2>
@@ -50,33 +51,15 @@ func TestPart1(t *testing.T) {
 #   #  ###
 	`
 
-	actual, _ := Part1(testInput)
-	if !stringEqualsIgnoringWhitespace(actual, expected) {
-		t.Errorf("expected:\n%s\n, actual:\n%s\n", expected, actual)
-	}
-}
-
-func stringEqualsIgnoringWhitespace(str1 string, str2 string) bool {
-	lines1, lines2 := splitLines(str1), splitLines(str2)
-	if len(lines1) != len(lines2) {
-		return false
+	actual, err := Part1(testInput)
+	if err != nil {
+		t.Fatal(err)
 	}
-	for i := range lines1 {
-		if strings.TrimSpace(lines1[i]) != strings.TrimSpace(lines2[i]) {
-			return false
-		}
-	}
-	return true
-}
-
-func splitLines(str string) []string {
-	return strings.Split(strings.TrimSpace(str), "\n")
+	testutil.AssertGridEqual(t, expected, actual)
 }
 
 func TestPart2(t *testing.T) {
-	expected := "3"
-	actual, _ := Part2(testInput)
-	if actual != expected {
-		t.Errorf("expected %s, actual %s", expected, actual)
-	}
+	testutil.RunExamples(t, Part2, []testutil.Example{
+		{Name: "example", Input: testInput, Expected: "3"},
+	})
 }
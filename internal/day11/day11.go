@@ -5,6 +5,8 @@ import (
 	"math"
 	"strconv"
 	"strings"
+
+	"github.com/orn688/advent-of-code-2018/internal/util"
 )
 
 // Part1 returns the max-sum 3x3 square in the grid.
@@ -19,13 +21,12 @@ func Part1(input string) (string, error) {
 }
 
 func max3x3Square(serialNumber, width, height int) (maxSquareX, maxSquareY int) {
-	grid := makeGrid(width, height)
-	setPowerLevels(grid, serialNumber)
+	table := powerLevelTable(serialNumber, width, height)
 
 	maxSquareSum := math.MinInt32
 	for y := 0; y < height-2; y++ {
 		for x := 0; x < width-2; x++ {
-			squareSum := calculateSquareSum(grid, x, y, 3)
+			squareSum := table.SquareSum(x, y, 3)
 			if squareSum > maxSquareSum {
 				maxSquareSum = squareSum
 				maxSquareX = x + 1
@@ -37,7 +38,6 @@ func max3x3Square(serialNumber, width, height int) (maxSquareX, maxSquareY int)
 }
 
 // Part2 returns the max-sum square of any size in the grid.
-// TODO: optimize (it does a very slow brute force)
 func Part2(input string) (string, error) {
 	serialNumber, err := strconv.Atoi(strings.TrimSpace(input))
 	if err != nil {
@@ -48,19 +48,20 @@ func Part2(input string) (string, error) {
 	return fmt.Sprintf("%d,%d,%d", x, y, size), nil
 }
 
+// maxSquare checks every square in the grid, which is O(n^3) because each
+// square's sum is looked up in constant time.
 func maxSquare(serialNumber, width, height int) (maxSquareX, maxSquareY, maxSquareSize int) {
-	grid := makeGrid(width, height)
-	setPowerLevels(grid, serialNumber)
+	table := powerLevelTable(serialNumber, width, height)
 
 	maxSquareSum := math.MinInt32
 	for y := 0; y < height; y++ {
 		for x := 0; x < width; x++ {
-			maxSize := width - y
-			if x > y {
-				maxSize = width - x
+			maxSize := width - x
+			if height-y < maxSize {
+				maxSize = height - y
 			}
 			for size := 1; size <= maxSize; size++ {
-				squareSum := calculateSquareSum(grid, x, y, size)
+				squareSum := table.SquareSum(x, y, size)
 				if squareSum > maxSquareSum {
 					maxSquareSum = squareSum
 					maxSquareX = x + 1
@@ -73,6 +74,12 @@ func maxSquare(serialNumber, width, height int) (maxSquareX, maxSquareY, maxSqua
 	return
 }
 
+func powerLevelTable(serialNumber, width, height int) *util.SummedAreaTable {
+	grid := makeGrid(width, height)
+	setPowerLevels(grid, serialNumber)
+	return util.NewSummedAreaTable(grid)
+}
+
 func makeGrid(width, height int) [][]int {
 	grid := make([][]int, height)
 	for y := range grid {
@@ -93,12 +100,3 @@ func setPowerLevels(grid [][]int, serialNumber int) {
 		}
 	}
 }
-
-func calculateSquareSum(grid [][]int, leftX, topY, size int) (sum int) {
-	for y := topY; y < topY+size; y++ {
-		for x := leftX; x < leftX+size; x++ {
-			sum += grid[y][x]
-		}
-	}
-	return
-}
@@ -42,19 +42,18 @@ func TestPart2(t *testing.T) {
 		expectedSize int
 	}
 	testcases := []testcase{
-		// Commented out because they're VERY slow.
-		// {
-		// 	serialNumber: 18,
-		// 	expectedX:    90,
-		// 	expectedY:    269,
-		// 	expectedSize: 16,
-		// },
-		// {
-		// 	serialNumber: 42,
-		// 	expectedX:    232,
-		// 	expectedY:    251,
-		// 	expectedSize: 12,
-		// },
+		{
+			serialNumber: 18,
+			expectedX:    90,
+			expectedY:    269,
+			expectedSize: 16,
+		},
+		{
+			serialNumber: 42,
+			expectedX:    232,
+			expectedY:    251,
+			expectedSize: 12,
+		},
 	}
 
 	for _, tc := range testcases {
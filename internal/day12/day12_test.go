@@ -1,8 +1,9 @@
 package day12
 
 import (
-	"strconv"
 	"testing"
+
+	"github.com/orn688/advent-of-code-2018/internal/testutil"
 )
 
 const input = `initial state: #..#.#..##......###...###
@@ -24,19 +25,15 @@ const input = `initial state: #..#.#..##......###...###
 `
 
 func TestPart1(t *testing.T) {
-	expected := 325
-	actual, _ := Part1(input)
-	if actual != strconv.Itoa(expected) {
-		t.Errorf("expected %d, actual %s", expected, actual)
-	}
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Name: "example", Input: input, Expected: "325"},
+	})
 }
 
 func TestPart2(t *testing.T) {
 	// This expected value wasn't given by AoC, but is produced by a version of
 	// the program that produced the correct value given the real input.
-	expected := 999999999374
-	actual, _ := Part2(input)
-	if actual != strconv.Itoa(expected) {
-		t.Errorf("expected %d, actual %s", expected, actual)
-	}
+	testutil.RunExamples(t, Part2, []testutil.Example{
+		{Name: "example", Input: input, Expected: "999999999374"},
+	})
 }
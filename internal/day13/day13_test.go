@@ -2,6 +2,8 @@ package day13
 
 import (
 	"testing"
+
+	"github.com/orn688/advent-of-code-2018/internal/testutil"
 )
 
 func TestPart1(t *testing.T) {
@@ -13,11 +15,9 @@ func TestPart1(t *testing.T) {
 \-+-/  \-+--/
   \------/
 `
-	expected := "7,3"
-	actual, _ := Part1(input)
-	if actual != expected {
-		t.Errorf("expected %s actual %s", expected, actual)
-	}
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Name: "example", Input: input, Expected: "7,3"},
+	})
 }
 
 func TestPart2(t *testing.T) {
@@ -30,9 +30,7 @@ func TestPart2(t *testing.T) {
   |   ^
   \<->/
 `
-	expected := "6,4"
-	actual, _ := Part2(input)
-	if actual != expected {
-		t.Errorf("expected %s actual %s", expected, actual)
-	}
+	testutil.RunExamples(t, Part2, []testutil.Example{
+		{Name: "example", Input: input, Expected: "6,4"},
+	})
 }
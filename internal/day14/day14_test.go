@@ -2,65 +2,26 @@ package day14
 
 import (
 	"testing"
-)
 
-func assertCorrect(t *testing.T, actual, expected string) {
-	t.Helper()
-	if actual != expected {
-		t.Errorf("got '%s', expected '%s'", actual, expected)
-	}
-}
+	"github.com/orn688/advent-of-code-2018/internal/testutil"
+)
 
 func TestPart1(t *testing.T) {
-	t.Run("5 recipes", func(t *testing.T) {
-		result, _ := Part1("5")
-		assertCorrect(t, result, "0124515891")
-	})
-
-	t.Run("9 recipes", func(t *testing.T) {
-		result, _ := Part1("9")
-		assertCorrect(t, result, "5158916779")
-	})
-
-	t.Run("18 recipes", func(t *testing.T) {
-		result, _ := Part1("18")
-		assertCorrect(t, result, "9251071085")
-	})
-
-	t.Run("2018 recipes", func(t *testing.T) {
-		result, _ := Part1("2018")
-		assertCorrect(t, result, "5941429882")
+	testutil.RunExamples(t, Part1, []testutil.Example{
+		{Name: "5 recipes", Input: "5", Expected: "0124515891"},
+		{Name: "9 recipes", Input: "9", Expected: "5158916779"},
+		{Name: "18 recipes", Input: "18", Expected: "9251071085"},
+		{Name: "2018 recipes", Input: "2018", Expected: "5941429882"},
 	})
 }
 
 func TestPart2(t *testing.T) {
-	t.Run("sequence 101", func(t *testing.T) {
-		result, _ := Part2("101")
-		assertCorrect(t, result, "2")
-	})
-
-	t.Run("sequence 01", func(t *testing.T) {
-		result, _ := Part2("01")
-		assertCorrect(t, result, "3")
-	})
-
-	t.Run("sequence 01245", func(t *testing.T) {
-		result, _ := Part2("01245")
-		assertCorrect(t, result, "5")
-	})
-
-	t.Run("sequence 51589", func(t *testing.T) {
-		result, _ := Part2("51589")
-		assertCorrect(t, result, "9")
-	})
-
-	t.Run("sequence 92510", func(t *testing.T) {
-		result, _ := Part2("92510")
-		assertCorrect(t, result, "18")
-	})
-
-	t.Run("sequence 59414", func(t *testing.T) {
-		result, _ := Part2("59414")
-		assertCorrect(t, result, "2018")
+	testutil.RunExamples(t, Part2, []testutil.Example{
+		{Name: "sequence 101", Input: "101", Expected: "2"},
+		{Name: "sequence 01", Input: "01", Expected: "3"},
+		{Name: "sequence 01245", Input: "01245", Expected: "5"},
+		{Name: "sequence 51589", Input: "51589", Expected: "9"},
+		{Name: "sequence 92510", Input: "92510", Expected: "18"},
+		{Name: "sequence 59414", Input: "59414", Expected: "2018"},
 	})
 }
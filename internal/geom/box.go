@@ -0,0 +1,99 @@
+package geom
+
+// A Box3 is an axis-aligned box in integer 3-space. Like a Rect, it contains
+// the points from Min up to but not including Max along each axis.
+type Box3 struct {
+	Min Point3
+	Max Point3
+}
+
+// BoundingBox3 returns the smallest box containing all of the given points,
+// or an empty Box3 if there are none.
+func BoundingBox3(points []Point3) Box3 {
+	if len(points) == 0 {
+		return Box3{}
+	}
+	b := Box3{points[0], points[0]}
+	for _, pt := range points[1:] {
+		b.Min = Point3{minInt(b.Min.X, pt.X), minInt(b.Min.Y, pt.Y), minInt(b.Min.Z, pt.Z)}
+		b.Max = Point3{maxInt(b.Max.X, pt.X), maxInt(b.Max.Y, pt.Y), maxInt(b.Max.Z, pt.Z)}
+	}
+	// Max is exclusive.
+	b.Max = b.Max.Add(Point3{1, 1, 1})
+	return b
+}
+
+// Grow returns b extended by n on every side, or shrunk if n is negative.
+func (b Box3) Grow(n int) Box3 {
+	return Box3{b.Min.Sub(Point3{n, n, n}), b.Max.Add(Point3{n, n, n})}
+}
+
+// Empty reports whether b contains no points.
+func (b Box3) Empty() bool {
+	return b.Min.X >= b.Max.X || b.Min.Y >= b.Max.Y || b.Min.Z >= b.Max.Z
+}
+
+// Volume returns the number of integer points in b.
+func (b Box3) Volume() int {
+	if b.Empty() {
+		return 0
+	}
+	size := b.Max.Sub(b.Min)
+	return size.X * size.Y * size.Z
+}
+
+// Contains reports whether p lies within b.
+func (b Box3) Contains(p Point3) bool {
+	return b.Min.X <= p.X && p.X < b.Max.X &&
+		b.Min.Y <= p.Y && p.Y < b.Max.Y &&
+		b.Min.Z <= p.Z && p.Z < b.Max.Z
+}
+
+// A Box4 is an axis-aligned box in integer 4-space, like a Box3.
+type Box4 struct {
+	Min Point4
+	Max Point4
+}
+
+// BoundingBox4 returns the smallest box containing all of the given points,
+// or an empty Box4 if there are none.
+func BoundingBox4(points []Point4) Box4 {
+	if len(points) == 0 {
+		return Box4{}
+	}
+	b := Box4{points[0], points[0]}
+	for _, pt := range points[1:] {
+		b.Min = Point4{minInt(b.Min.X, pt.X), minInt(b.Min.Y, pt.Y), minInt(b.Min.Z, pt.Z), minInt(b.Min.W, pt.W)}
+		b.Max = Point4{maxInt(b.Max.X, pt.X), maxInt(b.Max.Y, pt.Y), maxInt(b.Max.Z, pt.Z), maxInt(b.Max.W, pt.W)}
+	}
+	// Max is exclusive.
+	b.Max = b.Max.Add(Point4{1, 1, 1, 1})
+	return b
+}
+
+// Grow returns b extended by n on every side, or shrunk if n is negative.
+func (b Box4) Grow(n int) Box4 {
+	return Box4{b.Min.Sub(Point4{n, n, n, n}), b.Max.Add(Point4{n, n, n, n})}
+}
+
+// Empty reports whether b contains no points.
+func (b Box4) Empty() bool {
+	return b.Min.X >= b.Max.X || b.Min.Y >= b.Max.Y || b.Min.Z >= b.Max.Z || b.Min.W >= b.Max.W
+}
+
+// Volume returns the number of integer points in b.
+func (b Box4) Volume() int {
+	if b.Empty() {
+		return 0
+	}
+	size := b.Max.Sub(b.Min)
+	return size.X * size.Y * size.Z * size.W
+}
+
+// Contains reports whether p lies within b.
+func (b Box4) Contains(p Point4) bool {
+	return b.Min.X <= p.X && p.X < b.Max.X &&
+		b.Min.Y <= p.Y && p.Y < b.Max.Y &&
+		b.Min.Z <= p.Z && p.Z < b.Max.Z &&
+		b.Min.W <= p.W && p.W < b.Max.W
+}
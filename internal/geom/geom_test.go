@@ -0,0 +1,104 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistances(t *testing.T) {
+	p, q := Point2{1, 2}, Point2{4, -2}
+	if actual := p.Manhattan(q); actual != 7 {
+		t.Errorf("expected %d, actual %d", 7, actual)
+	}
+	if actual := p.Chebyshev(q); actual != 4 {
+		t.Errorf("expected %d, actual %d", 4, actual)
+	}
+	if actual := p.SquaredEuclidean(q); actual != 25 {
+		t.Errorf("expected %d, actual %d", 25, actual)
+	}
+	if actual := p.Euclidean(q); math.Abs(actual-5) > 1e-9 {
+		t.Errorf("expected %f, actual %f", 5.0, actual)
+	}
+
+	p4, q4 := Point4{0, 0, 0, 0}, Point4{1, -2, 3, -4}
+	if actual := p4.Manhattan(q4); actual != 10 {
+		t.Errorf("expected %d, actual %d", 10, actual)
+	}
+	if actual := p4.Chebyshev(q4); actual != 4 {
+		t.Errorf("expected %d, actual %d", 4, actual)
+	}
+	if actual := (Point3{1, 1, 1}).Add(Point3{1, 2, 3}).Scale(2); actual != (Point3{4, 6, 8}) {
+		t.Errorf("expected %v, actual %v", Point3{4, 6, 8}, actual)
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	points := []Point2{{1, 1}, {1, 6}, {8, 3}, {3, 4}, {5, 5}, {8, 9}}
+	expected := Rect{Point2{1, 1}, Point2{9, 10}}
+	actual := BoundingBox(points)
+	if actual != expected {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+	if actual.Area() != 72 {
+		t.Errorf("expected %d, actual %d", 72, actual.Area())
+	}
+	expected = Rect{Point2{-1, -1}, Point2{11, 12}}
+	if grown := actual.Grow(2); grown != expected {
+		t.Errorf("expected %v, actual %v", expected, grown)
+	}
+	if !BoundingBox(nil).Empty() {
+		t.Errorf("expected empty bounding box for no points")
+	}
+}
+
+func TestBoundingBox3And4(t *testing.T) {
+	box3 := BoundingBox3([]Point3{{1, -2, 3}, {4, 0, -1}})
+	if expected := (Box3{Point3{1, -2, -1}, Point3{5, 1, 4}}); box3 != expected {
+		t.Errorf("expected %v, actual %v", expected, box3)
+	}
+	if box3.Volume() != 4*3*5 || !box3.Contains(Point3{4, 0, 3}) || box3.Contains(Point3{5, 0, 3}) {
+		t.Errorf("expected a 4x3x5 box containing its corners, actual %v", box3)
+	}
+	if grown := box3.Grow(1); grown.Volume() != 6*5*7 || !grown.Contains(Point3{5, 0, 3}) {
+		t.Errorf("expected a 6x5x7 box, actual %v", grown)
+	}
+
+	box4 := BoundingBox4([]Point4{{0, 0, 0, 0}, {2, 1, 0, -3}})
+	if expected := (Box4{Point4{0, 0, 0, -3}, Point4{3, 2, 1, 1}}); box4 != expected {
+		t.Errorf("expected %v, actual %v", expected, box4)
+	}
+	if box4.Volume() != 3*2*1*4 || !box4.Contains(Point4{2, 1, 0, -3}) || box4.Contains(Point4{2, 1, 1, 0}) {
+		t.Errorf("expected a 3x2x1x4 box containing its corners, actual %v", box4)
+	}
+	if !box4.Grow(-1).Empty() || !BoundingBox3(nil).Empty() || !BoundingBox4(nil).Empty() {
+		t.Errorf("expected empty boxes")
+	}
+}
+
+func TestRectOverlap(t *testing.T) {
+	// The claims from the day 3 example.
+	r1 := RectFromSize(1, 3, 4, 4)
+	r2 := RectFromSize(3, 1, 4, 4)
+	r3 := RectFromSize(5, 5, 2, 2)
+
+	if !r1.Overlaps(r2) {
+		t.Errorf("expected %v to overlap %v", r1, r2)
+	}
+	if r1.Overlaps(r3) || r2.Overlaps(r3) {
+		t.Errorf("expected %v not to overlap the others", r3)
+	}
+	expected := RectFromSize(3, 3, 2, 2)
+	if actual := r1.Intersect(r2); actual != expected {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+	if actual := r1.Intersect(r3); actual != (Rect{}) {
+		t.Errorf("expected empty intersection, actual %v", actual)
+	}
+	expected = Rect{Point2{1, 1}, Point2{7, 7}}
+	if actual := r1.Union(r2).Union(r3); actual != expected {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+	if actual := (Rect{}).Union(r3); actual != r3 {
+		t.Errorf("expected %v, actual %v", r3, actual)
+	}
+}
@@ -0,0 +1,160 @@
+package geom
+
+import "math"
+
+// A Point2 is a point (or a vector) on the integer plane.
+type Point2 struct {
+	X int
+	Y int
+}
+
+// Add returns the vector sum p+q.
+func (p Point2) Add(q Point2) Point2 {
+	return Point2{p.X + q.X, p.Y + q.Y}
+}
+
+// Sub returns the vector difference p-q.
+func (p Point2) Sub(q Point2) Point2 {
+	return Point2{p.X - q.X, p.Y - q.Y}
+}
+
+// Scale returns the vector p multiplied by k.
+func (p Point2) Scale(k int) Point2 {
+	return Point2{p.X * k, p.Y * k}
+}
+
+// Manhattan returns the taxicab distance between p and q.
+func (p Point2) Manhattan(q Point2) int {
+	return Abs(p.X-q.X) + Abs(p.Y-q.Y)
+}
+
+// Chebyshev returns the chessboard distance between p and q.
+func (p Point2) Chebyshev(q Point2) int {
+	return maxInt(Abs(p.X-q.X), Abs(p.Y-q.Y))
+}
+
+// SquaredEuclidean returns the square of the straight-line distance between p
+// and q, which is exact and orders points the same way as Euclidean.
+func (p Point2) SquaredEuclidean(q Point2) int {
+	d := p.Sub(q)
+	return d.X*d.X + d.Y*d.Y
+}
+
+// Euclidean returns the straight-line distance between p and q.
+func (p Point2) Euclidean(q Point2) float64 {
+	return math.Sqrt(float64(p.SquaredEuclidean(q)))
+}
+
+// A Point3 is a point (or a vector) in integer 3-space.
+type Point3 struct {
+	X int
+	Y int
+	Z int
+}
+
+// Add returns the vector sum p+q.
+func (p Point3) Add(q Point3) Point3 {
+	return Point3{p.X + q.X, p.Y + q.Y, p.Z + q.Z}
+}
+
+// Sub returns the vector difference p-q.
+func (p Point3) Sub(q Point3) Point3 {
+	return Point3{p.X - q.X, p.Y - q.Y, p.Z - q.Z}
+}
+
+// Scale returns the vector p multiplied by k.
+func (p Point3) Scale(k int) Point3 {
+	return Point3{p.X * k, p.Y * k, p.Z * k}
+}
+
+// Manhattan returns the taxicab distance between p and q.
+func (p Point3) Manhattan(q Point3) int {
+	return Abs(p.X-q.X) + Abs(p.Y-q.Y) + Abs(p.Z-q.Z)
+}
+
+// Chebyshev returns the chessboard distance between p and q.
+func (p Point3) Chebyshev(q Point3) int {
+	return maxInt(Abs(p.X-q.X), maxInt(Abs(p.Y-q.Y), Abs(p.Z-q.Z)))
+}
+
+// SquaredEuclidean returns the square of the straight-line distance between p
+// and q.
+func (p Point3) SquaredEuclidean(q Point3) int {
+	d := p.Sub(q)
+	return d.X*d.X + d.Y*d.Y + d.Z*d.Z
+}
+
+// Euclidean returns the straight-line distance between p and q.
+func (p Point3) Euclidean(q Point3) float64 {
+	return math.Sqrt(float64(p.SquaredEuclidean(q)))
+}
+
+// A Point4 is a point (or a vector) in integer 4-space.
+type Point4 struct {
+	X int
+	Y int
+	Z int
+	W int
+}
+
+// Add returns the vector sum p+q.
+func (p Point4) Add(q Point4) Point4 {
+	return Point4{p.X + q.X, p.Y + q.Y, p.Z + q.Z, p.W + q.W}
+}
+
+// Sub returns the vector difference p-q.
+func (p Point4) Sub(q Point4) Point4 {
+	return Point4{p.X - q.X, p.Y - q.Y, p.Z - q.Z, p.W - q.W}
+}
+
+// Scale returns the vector p multiplied by k.
+func (p Point4) Scale(k int) Point4 {
+	return Point4{p.X * k, p.Y * k, p.Z * k, p.W * k}
+}
+
+// Manhattan returns the taxicab distance between p and q.
+func (p Point4) Manhattan(q Point4) int {
+	return Abs(p.X-q.X) + Abs(p.Y-q.Y) + Abs(p.Z-q.Z) + Abs(p.W-q.W)
+}
+
+// Chebyshev returns the chessboard distance between p and q.
+func (p Point4) Chebyshev(q Point4) int {
+	return maxInt(
+		maxInt(Abs(p.X-q.X), Abs(p.Y-q.Y)),
+		maxInt(Abs(p.Z-q.Z), Abs(p.W-q.W)),
+	)
+}
+
+// SquaredEuclidean returns the square of the straight-line distance between p
+// and q.
+func (p Point4) SquaredEuclidean(q Point4) int {
+	d := p.Sub(q)
+	return d.X*d.X + d.Y*d.Y + d.Z*d.Z + d.W*d.W
+}
+
+// Euclidean returns the straight-line distance between p and q.
+func (p Point4) Euclidean(q Point4) float64 {
+	return math.Sqrt(float64(p.SquaredEuclidean(q)))
+}
+
+// Abs returns the absolute value of x.
+func Abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,110 @@
+package geom
+
+// A Rect is an axis-aligned rectangle on the integer plane. It contains the
+// points p with Min.X <= p.X < Max.X and Min.Y <= p.Y < Max.Y, so a Rect whose
+// Min and Max are equal along either axis is empty.
+type Rect struct {
+	Min Point2
+	Max Point2
+}
+
+// RectFromSize returns the rectangle with top-left corner (x, y) and the given
+// width and height.
+func RectFromSize(x, y, width, height int) Rect {
+	return Rect{Point2{x, y}, Point2{x + width, y + height}}
+}
+
+// BoundingBox returns the smallest rectangle containing all of the given
+// points, or an empty Rect if there are none.
+func BoundingBox(points []Point2) Rect {
+	if len(points) == 0 {
+		return Rect{}
+	}
+	r := Rect{points[0], points[0]}
+	for _, pt := range points[1:] {
+		r.Min.X = minInt(r.Min.X, pt.X)
+		r.Min.Y = minInt(r.Min.Y, pt.Y)
+		r.Max.X = maxInt(r.Max.X, pt.X)
+		r.Max.Y = maxInt(r.Max.Y, pt.Y)
+	}
+	// Max is exclusive.
+	r.Max = r.Max.Add(Point2{1, 1})
+	return r
+}
+
+// Grow returns r extended by n on every side, or shrunk if n is negative.
+func (r Rect) Grow(n int) Rect {
+	return Rect{r.Min.Sub(Point2{n, n}), r.Max.Add(Point2{n, n})}
+}
+
+// Width returns the number of columns covered by r.
+func (r Rect) Width() int {
+	return r.Max.X - r.Min.X
+}
+
+// Height returns the number of rows covered by r.
+func (r Rect) Height() int {
+	return r.Max.Y - r.Min.Y
+}
+
+// Area returns the number of integer points in r.
+func (r Rect) Area() int {
+	if r.Empty() {
+		return 0
+	}
+	return r.Width() * r.Height()
+}
+
+// Empty reports whether r contains no points.
+func (r Rect) Empty() bool {
+	return r.Min.X >= r.Max.X || r.Min.Y >= r.Max.Y
+}
+
+// Contains reports whether p lies within r.
+func (r Rect) Contains(p Point2) bool {
+	return r.Min.X <= p.X && p.X < r.Max.X && r.Min.Y <= p.Y && p.Y < r.Max.Y
+}
+
+// Intersect returns the largest rectangle contained by both r and s. If the
+// two don't overlap then the empty Rect is returned.
+func (r Rect) Intersect(s Rect) Rect {
+	result := Rect{
+		Min: Point2{maxInt(r.Min.X, s.Min.X), maxInt(r.Min.Y, s.Min.Y)},
+		Max: Point2{minInt(r.Max.X, s.Max.X), minInt(r.Max.Y, s.Max.Y)},
+	}
+	if result.Empty() {
+		return Rect{}
+	}
+	return result
+}
+
+// Union returns the smallest rectangle that contains both r and s. Empty
+// rectangles are ignored.
+func (r Rect) Union(s Rect) Rect {
+	if r.Empty() {
+		return s
+	}
+	if s.Empty() {
+		return r
+	}
+	return Rect{
+		Min: Point2{minInt(r.Min.X, s.Min.X), minInt(r.Min.Y, s.Min.Y)},
+		Max: Point2{maxInt(r.Max.X, s.Max.X), maxInt(r.Max.Y, s.Max.Y)},
+	}
+}
+
+// Overlaps reports whether r and s share at least one point.
+func (r Rect) Overlaps(s Rect) bool {
+	return !r.Intersect(s).Empty()
+}
+
+// Points returns every point in r, in row-major order.
+func (r Rect) Points() []Point2 {
+	points := make([]Point2, 0, r.Area())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			points = append(points, Point2{x, y})
+		}
+	}
+	return points
+}
@@ -0,0 +1,169 @@
+// Package interval works with half-open ranges of integers and times, such as
+// the spans between events in a timestamped log.
+package interval
+
+import (
+	"fmt"
+	"sort"
+)
+
+// An Interval is the half-open range of integers [Start, End).
+type Interval struct {
+	Start int
+	End   int
+}
+
+// Len returns the number of integers in the interval.
+func (iv Interval) Len() int {
+	if iv.Empty() {
+		return 0
+	}
+	return iv.End - iv.Start
+}
+
+// Empty reports whether the interval contains no integers.
+func (iv Interval) Empty() bool {
+	return iv.End <= iv.Start
+}
+
+// Contains reports whether x lies within the interval.
+func (iv Interval) Contains(x int) bool {
+	return iv.Start <= x && x < iv.End
+}
+
+// Intersect returns the interval contained by both iv and other, or the empty
+// Interval if they don't overlap.
+func (iv Interval) Intersect(other Interval) Interval {
+	result := Interval{maxInt(iv.Start, other.Start), minInt(iv.End, other.End)}
+	if result.Empty() {
+		return Interval{}
+	}
+	return result
+}
+
+// Overlaps reports whether iv and other share at least one integer.
+func (iv Interval) Overlaps(other Interval) bool {
+	return !iv.Intersect(other).Empty()
+}
+
+// Merge returns the union of the given intervals as a sorted list of disjoint,
+// non-adjacent intervals. Empty intervals are dropped.
+func Merge(intervals []Interval) []Interval {
+	sorted := make([]Interval, 0, len(intervals))
+	for _, iv := range intervals {
+		if !iv.Empty() {
+			sorted = append(sorted, iv)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+
+	merged := []Interval{}
+	for _, iv := range sorted {
+		last := len(merged) - 1
+		if last >= 0 && iv.Start <= merged[last].End {
+			merged[last].End = maxInt(merged[last].End, iv.End)
+		} else {
+			merged = append(merged, iv)
+		}
+	}
+	return merged
+}
+
+// Wrap maps the interval onto a clock face of the given period, splitting it
+// wherever it passes 0. Every returned piece lies within [0, period), and an
+// interval longer than the period covers some values more than once.
+//
+// For example, with a period of 60, [58, 62) becomes [58, 60) and [0, 2). It
+// panics if the period isn't positive.
+func Wrap(iv Interval, period int) []Interval {
+	if period <= 0 {
+		panic(fmt.Sprintf("interval wrapped with non-positive period %d", period))
+	}
+	pieces := []Interval{}
+	for start := iv.Start; start < iv.End; {
+		pieceStart := start % period
+		if pieceStart < 0 {
+			pieceStart += period
+		}
+		pieceLen := minInt(period-pieceStart, iv.End-start)
+		pieces = append(pieces, Interval{pieceStart, pieceStart + pieceLen})
+		start += pieceLen
+	}
+	return pieces
+}
+
+// Histogram returns, for each integer x in [lo, hi), the number of intervals
+// that contain x. The count for x is stored at index x-lo.
+func Histogram(intervals []Interval, lo, hi int) []int {
+	if hi < lo {
+		hi = lo
+	}
+	// Record +1 where each interval starts and -1 where it ends, then take a
+	// running total.
+	deltas := make([]int, hi-lo+1)
+	for _, iv := range intervals {
+		clipped := iv.Intersect(Interval{lo, hi})
+		if clipped.Empty() {
+			continue
+		}
+		deltas[clipped.Start-lo]++
+		deltas[clipped.End-lo]--
+	}
+	counts := make([]int, hi-lo)
+	running := 0
+	for i := range counts {
+		running += deltas[i]
+		counts[i] = running
+	}
+	return counts
+}
+
+// MaxOverlap returns the smallest integer contained by the greatest number of
+// intervals, along with that number. If every interval is empty, it returns
+// (0, 0).
+func MaxOverlap(intervals []Interval) (point int, count int) {
+	type event struct {
+		at    int
+		delta int
+	}
+	events := make([]event, 0, 2*len(intervals))
+	for _, iv := range intervals {
+		if iv.Empty() {
+			continue
+		}
+		events = append(events, event{iv.Start, 1}, event{iv.End, -1})
+	}
+	// Ends sort before starts at the same point, since the intervals are
+	// half-open.
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].at != events[j].at {
+			return events[i].at < events[j].at
+		}
+		return events[i].delta < events[j].delta
+	})
+
+	running := 0
+	for _, e := range events {
+		running += e.delta
+		if running > count {
+			point, count = e.at, running
+		}
+	}
+	return
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
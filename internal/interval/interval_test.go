@@ -0,0 +1,79 @@
+package interval
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	intervals := []Interval{{5, 8}, {1, 3}, {2, 4}, {8, 9}, {10, 10}, {11, 12}}
+	expected := []Interval{{1, 4}, {5, 9}, {11, 12}}
+	actual := Merge(intervals)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	testcases := []struct {
+		iv       Interval
+		expected []Interval
+	}{
+		{Interval{5, 10}, []Interval{{5, 10}}},
+		{Interval{58, 62}, []Interval{{58, 60}, {0, 2}}},
+		{Interval{-2, 1}, []Interval{{58, 60}, {0, 1}}},
+		{Interval{30, 150}, []Interval{{30, 60}, {0, 60}, {0, 30}}},
+	}
+	for _, tc := range testcases {
+		actual := Wrap(tc.iv, 60)
+		if !reflect.DeepEqual(tc.expected, actual) {
+			t.Errorf("%v: expected %v, actual %v", tc.iv, tc.expected, actual)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a period of 0")
+		}
+	}()
+	Wrap(Interval{5, 10}, 0)
+}
+
+func TestHistogramAndMaxOverlap(t *testing.T) {
+	// The naps of guard #10 from the day 4 example.
+	naps := []Interval{{5, 25}, {30, 55}, {24, 29}}
+	counts := Histogram(naps, 0, 60)
+	if counts[24] != 2 || counts[25] != 1 || counts[29] != 0 || counts[54] != 1 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+	point, count := MaxOverlap(naps)
+	if point != 24 || count != 2 {
+		t.Errorf("expected (24, 2), actual (%d, %d)", point, count)
+	}
+	// Touching half-open intervals don't overlap.
+	point, count = MaxOverlap([]Interval{{3, 5}, {0, 3}})
+	if point != 0 || count != 1 {
+		t.Errorf("expected (0, 1), actual (%d, %d)", point, count)
+	}
+}
+
+func TestTimeIntervalAcrossMidnight(t *testing.T) {
+	start := time.Date(1518, 11, 1, 23, 58, 0, 0, time.UTC)
+	span := TimeInterval{start, start.Add(6 * time.Minute)}
+
+	expected := []Interval{{1438, 1440}, {0, 4}}
+	if actual := span.MinutesOfDay(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+
+	days := span.SplitDays()
+	if len(days) != 2 || days[0].Duration() != 2*time.Minute ||
+		days[1].Start.Day() != 2 || days[1].Duration() != 4*time.Minute {
+		t.Errorf("unexpected split: %v", days)
+	}
+
+	if actual := span.Minutes(start.Add(-time.Hour)); actual != (Interval{60, 66}) {
+		t.Errorf("expected %v, actual %v", Interval{60, 66}, actual)
+	}
+}
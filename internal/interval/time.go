@@ -0,0 +1,56 @@
+package interval
+
+import "time"
+
+// MinutesPerDay is the period of the clock used by MinutesOfDay.
+const MinutesPerDay = 24 * 60
+
+// A TimeInterval is the half-open span of time [Start, End).
+type TimeInterval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the span, or 0 if it is empty.
+func (t TimeInterval) Duration() time.Duration {
+	if !t.End.After(t.Start) {
+		return 0
+	}
+	return t.End.Sub(t.Start)
+}
+
+// Minutes returns the span as a range of whole minutes elapsed since origin.
+// Both ends are truncated to the minute.
+func (t TimeInterval) Minutes(origin time.Time) Interval {
+	return Interval{
+		Start: int(t.Start.Sub(origin) / time.Minute),
+		End:   int(t.End.Sub(origin) / time.Minute),
+	}
+}
+
+// MinutesOfDay returns the minutes of the day (0 for 00:00 through 1439 for
+// 23:59) covered by the span. A span that crosses midnight is split in two, so
+// a span from 23:58 to 00:02 covers [1438, 1440) and [0, 2).
+func (t TimeInterval) MinutesOfDay() []Interval {
+	startMinute := t.Start.Hour()*60 + t.Start.Minute()
+	length := int(t.Duration() / time.Minute)
+	return Wrap(Interval{startMinute, startMinute + length}, MinutesPerDay)
+}
+
+// SplitDays splits the span at each midnight (in the location of Start) that it
+// crosses.
+func (t TimeInterval) SplitDays() []TimeInterval {
+	pieces := []TimeInterval{}
+	start := t.Start
+	for start.Before(t.End) {
+		year, month, day := start.Date()
+		midnight := time.Date(year, month, day+1, 0, 0, 0, 0, start.Location())
+		end := t.End
+		if midnight.Before(end) {
+			end = midnight
+		}
+		pieces = append(pieces, TimeInterval{start, end})
+		start = end
+	}
+	return pieces
+}
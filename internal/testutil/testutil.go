@@ -0,0 +1,237 @@
+// Package testutil holds helpers shared by the solvers' tests.
+package testutil
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orn688/advent-of-code-2018/internal/client"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with actual output")
+
+// A Solver is the signature shared by each day's Part1 and Part2.
+type Solver func(input string) (string, error)
+
+// An Example is a single input to a Solver along with its expected output.
+type Example struct {
+	// Name is optional; the input is used when it is short enough.
+	Name     string
+	Input    string
+	Expected string
+}
+
+// RunExamples runs solver on each example as a subtest, failing any whose
+// output doesn't match or that returns an error.
+func RunExamples(t *testing.T, solver Solver, examples []Example) {
+	t.Helper()
+	for i, example := range examples {
+		example := example
+		t.Run(exampleName(i, example), func(t *testing.T) {
+			t.Helper()
+			actual, err := solver(example.Input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if actual != example.Expected {
+				t.Errorf("expected %s, actual %s", example.Expected, actual)
+			}
+		})
+	}
+}
+
+func exampleName(i int, example Example) string {
+	if example.Name != "" {
+		return example.Name
+	}
+	input := strings.TrimSpace(example.Input)
+	if len(input) <= 50 && !strings.Contains(input, "\n") {
+		return input
+	}
+	return fmt.Sprintf("example %d", i+1)
+}
+
+// AssertGridEqual fails the test if the two multi-line grids differ, printing
+// the rows that don't match. Trailing whitespace on each row and blank lines
+// around the grid are ignored, so expected values can be written as raw strings
+// that start on their own line.
+func AssertGridEqual(t *testing.T, expected, actual string) {
+	t.Helper()
+	if diff := GridDiff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+// GridDiff returns a human-readable description of how the two grids differ,
+// or "" if they are equal (as defined by AssertGridEqual).
+func GridDiff(expected, actual string) string {
+	expectedRows, actualRows := gridRows(expected), gridRows(actual)
+	rowCount := len(expectedRows)
+	if len(actualRows) > rowCount {
+		rowCount = len(actualRows)
+	}
+
+	var sb strings.Builder
+	equal := len(expectedRows) == len(actualRows)
+	for y := 0; y < rowCount; y++ {
+		expectedRow, actualRow := rowAt(expectedRows, y), rowAt(actualRows, y)
+		marker := " "
+		if expectedRow != actualRow {
+			marker = "!"
+			equal = false
+		}
+		fmt.Fprintf(&sb, "%s %3d  %-*s | %s\n",
+			marker, y, gridWidth(expectedRows), expectedRow, actualRow)
+	}
+	if equal {
+		return ""
+	}
+	return fmt.Sprintf(
+		"grids differ (expected %d rows, actual %d rows; expected on the left):\n%s",
+		len(expectedRows), len(actualRows), sb.String())
+}
+
+func gridRows(grid string) []string {
+	rows := strings.Split(grid, "\n")
+	for i := range rows {
+		rows[i] = strings.TrimRight(rows[i], " \t\r")
+	}
+	for len(rows) > 0 && rows[0] == "" {
+		rows = rows[1:]
+	}
+	for len(rows) > 0 && rows[len(rows)-1] == "" {
+		rows = rows[:len(rows)-1]
+	}
+	return rows
+}
+
+func rowAt(rows []string, y int) string {
+	if y < len(rows) {
+		return rows[y]
+	}
+	return ""
+}
+
+func gridWidth(rows []string) (width int) {
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	return
+}
+
+// AssertGolden compares actual to the contents of testdata/<name>.golden,
+// relative to the test's package. Run the tests with -update to (re)write the
+// golden file from actual.
+func AssertGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+	fileName := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(fileName, actual, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("%s (run with -update to create it)", err)
+	}
+	if string(expected) != string(actual) {
+		diff := GridDiff(string(expected), string(actual))
+		if diff == "" {
+			// The difference is only in whitespace that GridDiff ignores.
+			diff = fmt.Sprintf("expected %q\nactual   %q", expected, actual)
+		}
+		t.Errorf("output doesn't match %s (run with -update to accept it):\n%s", fileName, diff)
+	}
+}
+
+// AssertWriteErrors calls write with writers that fail after each possible
+// number of bytes, failing the test if write ignores the error, either by not
+// returning it or by carrying on writing.
+func AssertWriteErrors(t *testing.T, write func(w io.Writer) error) {
+	t.Helper()
+	var output bytes.Buffer
+	if err := write(&output); err != nil {
+		t.Fatal(err)
+	}
+	for n := 0; n < output.Len(); n++ {
+		w := &failingWriter{remaining: n}
+		err := write(w)
+		if err == nil {
+			t.Fatalf("expected an error from a writer that fails after %d of %d bytes", n, output.Len())
+		}
+		if w.writesAfterFailure > 0 {
+			t.Fatalf("expected no more writes after a failure after %d of %d bytes, actual %d",
+				n, output.Len(), w.writesAfterFailure)
+		}
+	}
+}
+
+var errWriteFailed = errors.New("write failed")
+
+// A failingWriter accepts a fixed number of bytes and then fails every write.
+type failingWriter struct {
+	remaining          int
+	failed             bool
+	writesAfterFailure int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.failed {
+		w.writesAfterFailure++
+		return 0, errWriteFailed
+	}
+	if len(p) > w.remaining {
+		w.failed = true
+		return w.remaining, errWriteFailed
+	}
+	w.remaining -= len(p)
+	return len(p), nil
+}
+
+// CachedInput returns the real puzzle input for the given day if it has been
+// downloaded into the repository's input cache, and skips the test otherwise.
+// It never makes a network request.
+func CachedInput(t *testing.T, day int) string {
+	t.Helper()
+	root, err := moduleRoot()
+	if err != nil {
+		t.Skip(err)
+	}
+	input, err := ioutil.ReadFile(filepath.Join(root, client.CacheDirName, fmt.Sprint(day)))
+	if err != nil {
+		t.Skipf("no cached input for day %d", day)
+	}
+	return string(input)
+}
+
+// moduleRoot walks up from the working directory to the one containing go.mod.
+func moduleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("go.mod not found")
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,61 @@
+package testutil
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGridDiff(t *testing.T) {
+	if diff := GridDiff("#..#\n#..#   \n\n", "\n#..#\n#..#"); diff != "" {
+		t.Errorf("expected no diff, actual:\n%s", diff)
+	}
+	diff := GridDiff("#..#\n#..#", "#..#\n##.#\n")
+	if !strings.Contains(diff, "!   1  #..# | ##.#") {
+		t.Errorf("expected row 1 to be marked, actual:\n%s", diff)
+	}
+	if strings.Contains(diff, "!   0") {
+		t.Errorf("expected row 0 not to be marked, actual:\n%s", diff)
+	}
+	if diff := GridDiff("#", "#\n#"); diff == "" {
+		t.Errorf("expected a diff for different row counts")
+	}
+}
+
+func TestRunExamples(t *testing.T) {
+	RunExamples(t, func(input string) (string, error) {
+		return strings.ToUpper(input), nil
+	}, []Example{
+		{Input: "abc", Expected: "ABC"},
+		{Name: "multi-line", Input: "a\nb", Expected: "A\nB"},
+	})
+}
+
+func TestFailingWriter(t *testing.T) {
+	w := &failingWriter{remaining: 5}
+	if n, err := fmt.Fprint(w, "abc"); n != 3 || err != nil {
+		t.Errorf("expected 3 bytes written, actual %d (%v)", n, err)
+	}
+	if n, err := fmt.Fprint(w, "def"); n != 2 || err != errWriteFailed {
+		t.Errorf("expected 2 bytes written and an error, actual %d (%v)", n, err)
+	}
+	if n, err := io.WriteString(w, "g"); n != 0 || err != errWriteFailed || w.writesAfterFailure != 1 {
+		t.Errorf("expected a write after the failure to be counted, actual %d (%v)", w.writesAfterFailure, err)
+	}
+}
+
+func TestCachedInputSkipsWhenAbsent(t *testing.T) {
+	skipped := false
+	t.Run("day 0", func(t *testing.T) {
+		defer func() {
+			skipped = t.Skipped()
+		}()
+		// There's no puzzle for day 0, so it's never cached.
+		CachedInput(t, 0)
+		t.Error("expected the test to be skipped")
+	})
+	if !skipped {
+		t.Error("expected the test to be skipped")
+	}
+}
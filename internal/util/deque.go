@@ -0,0 +1,119 @@
+package util
+
+// An IntDeque is a double-ended queue of ints backed by a circular slice. Once
+// it has grown to its working size, pushes, pops and rotations don't allocate.
+//
+// It can also be treated as a ring (such as a circle of marbles), with the
+// back of the deque as the current position.
+type IntDeque struct {
+	buf  []int
+	head int // index in buf of the front element
+	size int
+}
+
+// NewIntDeque returns an empty deque with room for capacity elements before it
+// needs to grow.
+func NewIntDeque(capacity int) *IntDeque {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &IntDeque{buf: make([]int, capacity)}
+}
+
+// Len returns the number of elements in the deque.
+func (d *IntDeque) Len() int {
+	return d.size
+}
+
+// At returns the i-th element from the front of the deque.
+func (d *IntDeque) At(i int) int {
+	if i < 0 || i >= d.size {
+		panic("deque index out of range")
+	}
+	return d.buf[d.index(i)]
+}
+
+// Front returns the element at the front of the deque without removing it.
+func (d *IntDeque) Front() int {
+	return d.At(0)
+}
+
+// Back returns the element at the back of the deque without removing it.
+func (d *IntDeque) Back() int {
+	return d.At(d.size - 1)
+}
+
+// PushFront adds x to the front of the deque.
+func (d *IntDeque) PushFront(x int) {
+	d.growIfFull()
+	d.head = d.index(-1)
+	d.buf[d.head] = x
+	d.size++
+}
+
+// PushBack adds x to the back of the deque.
+func (d *IntDeque) PushBack(x int) {
+	d.growIfFull()
+	d.buf[d.index(d.size)] = x
+	d.size++
+}
+
+// PopFront removes and returns the element at the front of the deque. It
+// panics if the deque is empty.
+func (d *IntDeque) PopFront() int {
+	x := d.Front()
+	d.head = d.index(1)
+	d.size--
+	return x
+}
+
+// PopBack removes and returns the element at the back of the deque. It panics
+// if the deque is empty.
+func (d *IntDeque) PopBack() int {
+	x := d.Back()
+	d.size--
+	return x
+}
+
+// Rotate moves n elements from the back of the deque to the front, or -n
+// elements from the front to the back if n is negative. Viewing the deque as
+// a ring, this moves the current position n steps counter-clockwise.
+func (d *IntDeque) Rotate(n int) {
+	if d.size <= 1 {
+		return
+	}
+	n %= d.size
+	if d.size == len(d.buf) {
+		// The buffer is full, so moving the head is all that's needed.
+		d.head = d.index(-n)
+		return
+	}
+	for ; n > 0; n-- {
+		d.PushFront(d.PopBack())
+	}
+	for ; n < 0; n++ {
+		d.PushBack(d.PopFront())
+	}
+}
+
+// index converts an offset from the front of the deque to an index into buf.
+// The offset may be negative.
+func (d *IntDeque) index(offset int) int {
+	i := (d.head + offset) % len(d.buf)
+	if i < 0 {
+		i += len(d.buf)
+	}
+	return i
+}
+
+func (d *IntDeque) growIfFull() {
+	if d.size < len(d.buf) {
+		return
+	}
+	buf := make([]int, 2*len(d.buf))
+	for i := 0; i < d.size; i++ {
+		buf[i] = d.buf[d.index(i)]
+	}
+	d.buf = buf
+	d.head = 0
+}
@@ -0,0 +1,66 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func dequeContents(d *IntDeque) []int {
+	contents := make([]int, d.Len())
+	for i := range contents {
+		contents[i] = d.At(i)
+	}
+	return contents
+}
+
+func TestIntDeque(t *testing.T) {
+	// With a capacity of 5 the buffer ends up exactly full, which takes a
+	// different path through Rotate.
+	for _, capacity := range []int{2, 5} {
+		testIntDeque(t, NewIntDeque(capacity))
+	}
+}
+
+func testIntDeque(t *testing.T, d *IntDeque) {
+	t.Helper()
+	d.PushBack(2)
+	d.PushBack(3)
+	d.PushFront(1)
+	d.PushBack(4)
+	d.PushFront(0)
+	if expected, actual := []int{0, 1, 2, 3, 4}, dequeContents(d); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+
+	d.Rotate(2)
+	if expected, actual := []int{3, 4, 0, 1, 2}, dequeContents(d); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+	d.Rotate(-8)
+	if expected, actual := []int{1, 2, 3, 4, 0}, dequeContents(d); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+
+	if front, back := d.PopFront(), d.PopBack(); front != 1 || back != 0 {
+		t.Errorf("expected 1 and 0, actual %d and %d", front, back)
+	}
+	if expected, actual := []int{2, 3, 4}, dequeContents(d); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+}
+
+func TestIntDequeSteadyStateDoesNotAllocate(t *testing.T) {
+	d := NewIntDeque(16)
+	for i := 0; i < 10; i++ {
+		d.PushBack(i)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		d.Rotate(3)
+		d.PushBack(d.PopFront())
+		d.PushFront(d.PopBack())
+		d.Rotate(-4)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, actual %f", allocs)
+	}
+}
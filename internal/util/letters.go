@@ -0,0 +1,10 @@
+package util
+
+// LetterCounts returns the number of times each character appears in str.
+func LetterCounts(str string) map[rune]int {
+	counts := make(map[rune]int)
+	for _, letter := range str {
+		counts[letter]++
+	}
+	return counts
+}
@@ -0,0 +1,53 @@
+package util
+
+// A SummedAreaTable (also known as an integral image) holds the running sums of
+// a grid of integers, so that the sum of any rectangle within the grid can be
+// found in constant time.
+type SummedAreaTable struct {
+	// sums[y][x] holds the sum of all cells above and to the left of (x, y),
+	// exclusive. The extra row and column of zeros avoid bounds checks.
+	sums [][]int
+}
+
+// NewSummedAreaTable builds a table from a grid indexed as grid[y][x]. All rows
+// of the grid must have the same length.
+func NewSummedAreaTable(grid [][]int) *SummedAreaTable {
+	width := 0
+	if len(grid) > 0 {
+		width = len(grid[0])
+	}
+	sums := make([][]int, len(grid)+1)
+	sums[0] = make([]int, width+1)
+	for y, row := range grid {
+		sums[y+1] = make([]int, width+1)
+		rowSum := 0
+		for x, value := range row {
+			rowSum += value
+			sums[y+1][x+1] = sums[y][x+1] + rowSum
+		}
+	}
+	return &SummedAreaTable{sums}
+}
+
+// Width returns the number of columns in the underlying grid.
+func (t *SummedAreaTable) Width() int {
+	return len(t.sums[0]) - 1
+}
+
+// Height returns the number of rows in the underlying grid.
+func (t *SummedAreaTable) Height() int {
+	return len(t.sums) - 1
+}
+
+// RectSum returns the sum of the width x height rectangle whose top-left cell
+// is (x, y). The rectangle must lie entirely within the grid.
+func (t *SummedAreaTable) RectSum(x, y, width, height int) int {
+	x2, y2 := x+width, y+height
+	return t.sums[y2][x2] - t.sums[y][x2] - t.sums[y2][x] + t.sums[y][x]
+}
+
+// SquareSum returns the sum of the size x size square whose top-left cell is
+// (x, y).
+func (t *SummedAreaTable) SquareSum(x, y, size int) int {
+	return t.RectSum(x, y, size, size)
+}
@@ -0,0 +1,39 @@
+package util
+
+import "testing"
+
+func TestSummedAreaTable(t *testing.T) {
+	grid := [][]int{
+		{31, 2, 4, 33, 5, 36},
+		{12, 26, 9, 10, 29, 25},
+		{13, 17, 21, 22, 20, 18},
+		{24, 23, 15, 16, 14, 19},
+		{30, 8, 28, 27, 11, 7},
+		{1, 35, 34, 3, 32, 6},
+	}
+	table := NewSummedAreaTable(grid)
+
+	for y := 0; y < len(grid); y++ {
+		for x := 0; x < len(grid[0]); x++ {
+			for height := 0; y+height <= len(grid); height++ {
+				for width := 0; x+width <= len(grid[0]); width++ {
+					expected := 0
+					for j := y; j < y+height; j++ {
+						for i := x; i < x+width; i++ {
+							expected += grid[j][i]
+						}
+					}
+					actual := table.RectSum(x, y, width, height)
+					if actual != expected {
+						t.Errorf("(%d,%d) %dx%d: expected %d, actual %d",
+							x, y, width, height, expected, actual)
+					}
+				}
+			}
+		}
+	}
+
+	if expected, actual := 666, table.SquareSum(0, 0, 6); actual != expected {
+		t.Errorf("expected %d, actual %d", expected, actual)
+	}
+}
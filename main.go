@@ -27,6 +27,10 @@ import (
 
 type dayfunc func(string) (string, error)
 
+// A caveatfunc is like a dayfunc, but also returns any caveats about the
+// answer, to be shown alongside it.
+type caveatfunc func(string) (string, []string, error)
+
 func main() {
 	app := cli.NewApp()
 	app.Name = "Advent of Code 2018"
@@ -37,6 +41,7 @@ func main() {
 		},
 	}
 	app.Action = action
+	app.Commands = dayCommands()
 	err := app.Run(os.Args)
 	if err != nil {
 		fmt.Println(err)
@@ -66,17 +71,21 @@ func runDay(day int, part2 bool) error {
 	if err != nil {
 		return err
 	}
-	result, err := fun(input)
+	result, caveats, err := fun(input)
 	if err != nil {
 		return err
 	}
 
 	fmt.Println(result)
+	for _, caveat := range caveats {
+		warn(caveat)
+	}
 
 	return nil
 }
 
-func funcForDay(day int, part2 bool) (fun dayfunc, err error) {
+func funcForDay(day int, part2 bool) (caveatfunc, error) {
+	var fun dayfunc
 	switch day {
 	case 1:
 		fun = day01.Part1
@@ -94,10 +103,10 @@ func funcForDay(day int, part2 bool) (fun dayfunc, err error) {
 			fun = day03.Part2
 		}
 	case 4:
-		fun = day04.Part1
 		if part2 {
-			fun = day04.Part2
+			return day04.Part2WithCaveats, nil
 		}
+		return day04.Part1WithCaveats, nil
 	case 5:
 		fun = day05.Part1
 		if part2 {
@@ -149,7 +158,10 @@ func funcForDay(day int, part2 bool) (fun dayfunc, err error) {
 			fun = day14.Part2
 		}
 	default:
-		err = fmt.Errorf("day %d is not implemented", day)
+		return nil, fmt.Errorf("day %d is not implemented", day)
 	}
-	return fun, err
+	return func(input string) (string, []string, error) {
+		result, err := fun(input)
+		return result, nil, err
+	}, nil
 }